@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/publish"
+	"github.com/JackPlowman/coding-metrics/render"
+	"github.com/JackPlowman/coding-metrics/server"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+const cliUsage = `Usage: coding-metrics [command] [flags]
+
+Commands:
+  run      fetch metrics, render the SVG and publish it (default)
+  fetch    fetch metrics and write them as JSON
+  render   render an SVG from a metrics JSON file
+  publish  publish an existing SVG file
+  themes   list the available colour profiles
+  serve    serve cards for any user over HTTP
+
+Inputs can be set with flags, INPUT_* environment variables or the config
+file, in that order of precedence. Run "coding-metrics <command> -h" to list
+the flags of a command.
+`
+
+// cliOptions holds the command specific flags
+type cliOptions struct {
+	configPath  string
+	input       string
+	output      string
+	metricsPath string
+	addr        string
+	cacheTTL    time.Duration
+	cacheDir    string
+	cacheSize   int
+	rateLimit   int
+}
+
+// runCLI parses the command line and runs the selected command
+func runCLI(args []string) error {
+	command := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("coding-metrics "+command, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), cliUsage+"\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	options := cliOptions{}
+	flags.StringVar(
+		&options.configPath,
+		"config",
+		"",
+		"path to the config file (env INPUT_CONFIG_FILE, default "+defaultConfigFile+")",
+	)
+	switch command {
+	case "run", "themes":
+	case "fetch":
+		flags.StringVar(
+			&options.output,
+			"o",
+			"",
+			"file to write the metrics JSON to (default stdout)",
+		)
+	case "render":
+		flags.StringVar(&options.input, "i", "", "metrics JSON file to render (default stdin)")
+		flags.StringVar(&options.output, "o", "", "file to write the SVG to (default stdout)")
+	case "publish":
+		flags.StringVar(&options.input, "f", "", "SVG file to publish")
+		flags.StringVar(
+			&options.metricsPath,
+			"metrics",
+			"",
+			"metrics JSON file used for the README stats table and step outputs",
+		)
+	case "serve":
+		flags.StringVar(&options.addr, "addr", ":8080", "address to listen on")
+		flags.DurationVar(
+			&options.cacheTTL,
+			"cache-ttl",
+			server.DefaultTTL,
+			"how long fetched metrics are served before being fetched again",
+		)
+		flags.StringVar(
+			&options.cacheDir,
+			"cache-dir",
+			"",
+			"directory to cache fetched metrics in (default in memory)",
+		)
+		flags.IntVar(
+			&options.cacheSize,
+			"cache-size",
+			server.DefaultCacheSize,
+			"number of users whose metrics are cached in memory",
+		)
+		flags.IntVar(
+			&options.rateLimit,
+			"rate-limit",
+			server.DefaultRateLimit,
+			"metrics fetches allowed per user per minute, 0 for no limit",
+		)
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, cliUsage)
+	}
+	registerInputFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	if err := initInputs(flags, options.configPath); err != nil {
+		return err
+	}
+	logger, err := initLogger()
+	zap.ReplaceGlobals(zap.Must(logger, err))
+
+	switch command {
+	case "fetch":
+		return runFetch(options)
+	case "render":
+		return runRender(options)
+	case "publish":
+		return runPublish(options)
+	case "themes":
+		return runThemes(os.Stdout)
+	case "serve":
+		return runServe(options)
+	default:
+		return runAction()
+	}
+}
+
+// runAction fetches the metrics, renders the SVG and publishes it
+func runAction() error {
+	document, err := collectMetrics()
+	if err != nil {
+		return err
+	}
+	card, err := renderSVG(newRenderer(), document, getInput("output_section"))
+	if err != nil {
+		return err
+	}
+	file := createLocalFile(card)
+	files, err := svgFiles(file, document)
+	if err != nil {
+		return err
+	}
+	if err := writeLocalOutputs(files); err != nil {
+		return err
+	}
+	result := publishSVG(files, document.Totals)
+	writeActionOutputs(file, result, document.Totals)
+	return nil
+}
+
+// runFetch fetches the metrics and writes them as JSON
+func runFetch(options cliOptions) error {
+	document, err := collectMetrics()
+	if err != nil {
+		return err
+	}
+	if options.output == "" {
+		return metrics.Write(os.Stdout, document)
+	}
+
+	// #nosec G304 -- The output path is provided by the user.
+	file, err := os.Create(options.output)
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	if err := metrics.Write(file, document); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// runRender renders the SVG from a metrics JSON file
+func runRender(options cliOptions) error {
+	document, err := readMetricsFile(options.input)
+	if err != nil {
+		return err
+	}
+	card, err := renderSVG(newRenderer(), document, getInput("output_section"))
+	if err != nil {
+		return err
+	}
+	if options.output == "" {
+		_, err := card.WriteTo(os.Stdout)
+		return err
+	}
+	file := createFile(card, options.output)
+	zap.L().Info("Rendered SVG", zap.String("path", file.Name()))
+	for _, output := range currentConfig.Outputs {
+		element, err := renderSVG(newOutputRenderer(output), document, output.Section)
+		if err != nil {
+			return err
+		}
+		// Outputs keep the directories of their names, as they are published
+		createFile(element, localPath(filepath.Dir(options.output), output.FileName))
+	}
+	return nil
+}
+
+// runPublish publishes an existing SVG file
+func runPublish(options cliOptions) error {
+	if options.input == "" {
+		return fmt.Errorf("the SVG file to publish must be set with -f")
+	}
+	// #nosec G304 -- The SVG path is provided by the user.
+	file, err := os.Open(options.input)
+	if err != nil {
+		return fmt.Errorf("failed to open SVG file: %w", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			zap.L().Warn("Failed to close SVG file", zap.Error(cerr))
+		}
+	}()
+
+	var document *metrics.Metrics
+	var totals *metrics.GitHubTotalsStats
+	if options.metricsPath != "" {
+		document, err = readMetricsFile(options.metricsPath)
+		if err != nil {
+			return err
+		}
+		totals = document.Totals
+	}
+	files, err := svgFiles(file, document)
+	if err != nil {
+		return err
+	}
+	result := publishSVG(files, totals)
+	writeActionOutputs(file, result, totals)
+	return nil
+}
+
+// runServe serves cards over HTTP until interrupted
+func runServe(options cliOptions) error {
+	provider, err := newProvider()
+	if err != nil {
+		return err
+	}
+	s := server.New(provider, theme.GetColourProfile(getInput("colour_profile")))
+	if _, isGitHub := provider.(*metrics.Collector); !isGitHub {
+		s.LoginPattern = server.UsernamePattern
+	}
+	s.TTL = options.cacheTTL
+	s.Theme = currentConfig.Theme
+	s.Options = currentConfig.Render
+	s.Options.Size = getInput("card_size")
+	if !render.IsSize(s.Options.Size) {
+		return fmt.Errorf("unknown card size %q", s.Options.Size)
+	}
+	s.Options.LanguageChart = getInput("language_chart")
+	if !render.IsLanguageChart(s.Options.LanguageChart) {
+		return fmt.Errorf("unknown language chart %q", s.Options.LanguageChart)
+	}
+	s.Cache = server.NewMemoryCache(options.cacheSize, options.cacheTTL)
+	if options.cacheDir != "" {
+		cache, err := server.NewDiskCache(options.cacheDir)
+		if err != nil {
+			return err
+		}
+		s.Cache = cache
+	}
+	s.Limiter = nil
+	if options.rateLimit > 0 {
+		s.Limiter = server.NewRateLimiter(options.rateLimit, time.Minute)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	httpServer := &http.Server{
+		Addr:              options.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		zap.L().Info("Serving cards", zap.String("addr", options.addr))
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+	zap.L().Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// svgFiles returns the files to publish: the SVG file, named by the
+// output_file_name input, followed by the outputs of the config file rendered
+// from the document. The outputs are skipped if there is no document.
+func svgFiles(file *os.File, document *metrics.Metrics) ([]publish.File, error) {
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SVG file: %w", err)
+	}
+	files := []publish.File{{Name: getInput("output_file_name"), Content: content}}
+	if document == nil {
+		return files, nil
+	}
+	for _, output := range currentConfig.Outputs {
+		element, err := renderSVG(newOutputRenderer(output), document, output.Section)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if _, err := element.WriteTo(&buf); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", output.FileName, err)
+		}
+		files = append(files, publish.File{Name: output.FileName, Content: buf.Bytes()})
+	}
+	return files, nil
+}
+
+// runThemes lists the available colour profiles
+func runThemes(w io.Writer) error {
+	profiles := theme.GetAvailableProfiles()
+	sort.Strings(profiles)
+	for _, name := range profiles {
+		profile, _ := theme.LookupColourProfile(name)
+		if _, err := fmt.Fprintf(w, "%-10s %s\n", name, profile.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMetricsFile reads metrics JSON from the file at path, or stdin if the
+// path is empty.
+func readMetricsFile(path string) (*metrics.Metrics, error) {
+	if path == "" {
+		return metrics.Read(os.Stdin)
+	}
+	// #nosec G304 -- The metrics path is provided by the user.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics file: %w", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			zap.L().Warn("Failed to close metrics file", zap.Error(cerr))
+		}
+	}()
+	return metrics.Read(file)
+}
+
+// collectMetrics collects the metrics of the user from the data source
+// selected by the data_source input, for GitHub the user the github_token
+// input belongs to
+func collectMetrics() (*metrics.Metrics, error) {
+	provider, err := newProvider()
+	if err != nil {
+		return nil, err
+	}
+	document, err := provider.Collect(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect metrics: %w", err)
+	}
+	return document, nil
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/JackPlowman/coding-metrics/render"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+// configFile is the parsed coding-metrics.yml config file
+type configFile struct {
+	// Inputs holds the top level input values, e.g. colour_profile
+	Inputs map[string]string
+	Render render.Options
+	Theme  theme.Overrides
+	// Outputs are additional SVGs published together with the main one
+	Outputs []outputConfig
+}
+
+// outputConfig is an additional SVG rendered from the same metrics
+type outputConfig struct {
+	FileName string
+	// ColourProfile replaces the colour_profile input. Outputs with their own
+	// colour profile do not inherit the top level theme overrides.
+	ColourProfile string
+	Theme         theme.Overrides
+	// Size replaces the card_size input
+	Size string
+	// Section renders only this section instead of the card
+	Section string
+}
+
+// Global config - will be set in main from the config file
+var currentConfig = configFile{}
+
+var hexColourPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// configError is an invalid value in the config file, pointing at its key
+type configError struct {
+	File    string
+	Line    int
+	Column  int
+	Key     string
+	Message string
+}
+
+func (e *configError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Key, e.Message)
+}
+
+// configParser validates the config file while converting it, so errors can
+// point at the offending key
+type configParser struct {
+	file string
+}
+
+func (p configParser) errorf(node *yaml.Node, key, format string, args ...interface{}) error {
+	return &configError{
+		File:    p.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Key:     key,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// loadConfig reads the YAML config file. A missing file is only an error if
+// the path was set explicitly.
+func loadConfig(path string, explicit bool) (configFile, error) {
+	// #nosec G304 -- The config file path is provided by the user.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return configFile{}, nil
+		}
+		return configFile{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return parseConfig(path, content)
+}
+
+// parseConfig parses and validates the content of the config file
+func parseConfig(path string, content []byte) (configFile, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return configFile{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	config := configFile{Inputs: map[string]string{}}
+	if len(document.Content) == 0 {
+		return config, nil
+	}
+
+	p := configParser{file: path}
+	err := p.mapping(document.Content[0], "", func(key string, node *yaml.Node) error {
+		switch key {
+		case "render":
+			options, err := p.render(node, key)
+			config.Render = options
+			return err
+		case "theme":
+			overrides, err := p.theme(node, key)
+			config.Theme = overrides
+			return err
+		case "outputs":
+			outputs, err := p.outputs(node, key)
+			config.Outputs = outputs
+			return err
+		}
+		if !isInput(key) {
+			return errUnknownKey
+		}
+		if node.Kind != yaml.ScalarNode {
+			return p.errorf(node, key, "must be a scalar value")
+		}
+		if node.Tag == "!!null" {
+			return nil
+		}
+		if key == "colour_profile" {
+			if _, exists := theme.LookupColourProfile(node.Value); !exists {
+				return p.errorf(node, key, "unknown colour profile %q", node.Value)
+			}
+		}
+		if key == "output_section" && !render.IsSection(node.Value) {
+			return p.errorf(node, key, "unknown section %q", node.Value)
+		}
+		if key == "card_size" {
+			if _, err := p.oneOf(node, key, render.Sizes); err != nil {
+				return err
+			}
+		}
+		if key == "language_chart" {
+			if _, err := p.oneOf(node, key, render.LanguageCharts); err != nil {
+				return err
+			}
+		}
+		config.Inputs[key] = node.Value
+		return nil
+	})
+	return config, err
+}
+
+// errUnknownKey is returned, optionally wrapped with more detail, by mapping
+// callbacks for keys they do not accept, so the error points at the key
+var errUnknownKey = errors.New("unknown key")
+
+// mapping calls fn for every key of the mapping node in order, rejecting
+// other nodes and duplicate keys
+func (p configParser) mapping(
+	node *yaml.Node,
+	path string,
+	fn func(key string, value *yaml.Node) error,
+) error {
+	if node.Kind != yaml.MappingNode {
+		return p.errorf(node, displayKey(path), "must be a mapping")
+	}
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, value := node.Content[i], node.Content[i+1]
+		key := joinKey(path, keyNode.Value)
+		if seen[keyNode.Value] {
+			return p.errorf(keyNode, key, "duplicate key")
+		}
+		seen[keyNode.Value] = true
+		if err := fn(keyNode.Value, value); err != nil {
+			if errors.Is(err, errUnknownKey) {
+				return p.errorf(keyNode, key, "%s", err.Error())
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// render parses the render section
+func (p configParser) render(node *yaml.Node, path string) (render.Options, error) {
+	options := render.Options{}
+	err := p.mapping(node, path, func(key string, value *yaml.Node) error {
+		switch key {
+		case "sections":
+			sections, err := p.names(value, joinKey(path, key), "section", render.IsSection)
+			options.Sections = sections
+			return err
+		case "options":
+			return p.sectionOptions(value, joinKey(path, key), &options)
+		default:
+			return errUnknownKey
+		}
+	})
+	return options, err
+}
+
+// sectionOptions parses the options of each section
+func (p configParser) sectionOptions(
+	node *yaml.Node,
+	path string,
+	options *render.Options,
+) error {
+	return p.mapping(node, path, func(section string, value *yaml.Node) error {
+		sectionPath := joinKey(path, section)
+		if !render.IsSection(section) {
+			return fmt.Errorf("%w, expected a section name", errUnknownKey)
+		}
+		return p.mapping(value, sectionPath, func(key string, value *yaml.Node) error {
+			optionPath := joinKey(sectionPath, key)
+			switch section + "." + key {
+			case "languages.limit":
+				limit, err := p.positiveInt(value, optionPath)
+				options.LanguageLimit = limit
+				return err
+			case "languages.other_after":
+				after, err := p.positiveInt(value, optionPath)
+				options.OtherLanguagesAfter = after
+				return err
+			case "calendar.style":
+				style, err := p.oneOf(value, optionPath, render.CalendarStyles)
+				options.CalendarStyle = style
+				return err
+			case "stats.lines":
+				lines, err := p.names(value, optionPath, "stat line", render.IsStatLine)
+				options.StatLines = lines
+				return err
+			default:
+				return fmt.Errorf("%w for the %s section", errUnknownKey, section)
+			}
+		})
+	})
+}
+
+// theme parses colour overrides
+func (p configParser) theme(node *yaml.Node, path string) (theme.Overrides, error) {
+	overrides := theme.Overrides{}
+	colours := map[string]*string{
+		"background":       &overrides.Background,
+		"text_primary":     &overrides.TextPrimary,
+		"text_secondary":   &overrides.TextSecondary,
+		"accent_primary":   &overrides.AccentPrimary,
+		"accent_secondary": &overrides.AccentSecondary,
+	}
+	err := p.mapping(node, path, func(key string, value *yaml.Node) error {
+		keyPath := joinKey(path, key)
+		if colour, exists := colours[key]; exists {
+			parsed, err := p.colour(value, keyPath)
+			*colour = parsed
+			return err
+		}
+		if key == "language_colours" {
+			languageColours, err := p.languageColours(value, keyPath)
+			overrides.LanguageColours = languageColours
+			return err
+		}
+		if key != "contribution_levels" {
+			return errUnknownKey
+		}
+		if value.Kind != yaml.SequenceNode {
+			return p.errorf(value, keyPath, "must be a list of colours")
+		}
+		if len(value.Content) > 5 {
+			return p.errorf(value, keyPath, "must have at most 5 colours, one per level")
+		}
+		for i, item := range value.Content {
+			parsed, err := p.colour(item, fmt.Sprintf("%s[%d]", keyPath, i))
+			if err != nil {
+				return err
+			}
+			overrides.ContributionLevels = append(overrides.ContributionLevels, parsed)
+		}
+		return nil
+	})
+	return overrides, err
+}
+
+// languageColours parses colours keyed by language name
+func (p configParser) languageColours(node *yaml.Node, path string) (map[string]string, error) {
+	colours := map[string]string{}
+	err := p.mapping(node, path, func(key string, value *yaml.Node) error {
+		colour, err := p.colour(value, joinKey(path, key))
+		colours[key] = colour
+		return err
+	})
+	return colours, err
+}
+
+// outputs parses the additional outputs
+func (p configParser) outputs(node *yaml.Node, path string) ([]outputConfig, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, p.errorf(node, path, "must be a list of outputs")
+	}
+	outputs := []outputConfig{}
+	for i, item := range node.Content {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		output := outputConfig{}
+		err := p.mapping(item, itemPath, func(key string, value *yaml.Node) error {
+			keyPath := joinKey(itemPath, key)
+			switch key {
+			case "file_name":
+				name, err := p.string(value, keyPath)
+				output.FileName = name
+				return err
+			case "colour_profile":
+				name, err := p.string(value, keyPath)
+				if err != nil {
+					return err
+				}
+				if _, exists := theme.LookupColourProfile(name); !exists {
+					return p.errorf(value, keyPath, "unknown colour profile %q", name)
+				}
+				output.ColourProfile = name
+				return nil
+			case "theme":
+				overrides, err := p.theme(value, keyPath)
+				output.Theme = overrides
+				return err
+			case "size":
+				size, err := p.oneOf(value, keyPath, render.Sizes)
+				output.Size = size
+				return err
+			case "section":
+				section, err := p.string(value, keyPath)
+				if err != nil {
+					return err
+				}
+				if !render.IsSection(section) {
+					return p.errorf(value, keyPath, "unknown section %q", section)
+				}
+				output.Section = section
+				return nil
+			default:
+				return errUnknownKey
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		if output.FileName == "" {
+			return nil, p.errorf(item, itemPath, "file_name is required")
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// names parses a list of unique names accepted by valid
+func (p configParser) names(
+	node *yaml.Node,
+	path, kind string,
+	valid func(string) bool,
+) ([]string, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, p.errorf(node, path, "must be a list of %ss", kind)
+	}
+	names := []string{}
+	seen := map[string]bool{}
+	for i, item := range node.Content {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		name, err := p.string(item, itemPath)
+		if err != nil {
+			return nil, err
+		}
+		if !valid(name) {
+			return nil, p.errorf(item, itemPath, "unknown %s %q", kind, name)
+		}
+		if seen[name] {
+			return nil, p.errorf(item, itemPath, "duplicate %s %q", kind, name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (p configParser) string(node *yaml.Node, path string) (string, error) {
+	if node.Kind != yaml.ScalarNode || node.Tag == "!!null" || node.Value == "" {
+		return "", p.errorf(node, path, "must be a non-empty string")
+	}
+	return node.Value, nil
+}
+
+func (p configParser) positiveInt(node *yaml.Node, path string) (int, error) {
+	value, err := strconv.Atoi(node.Value)
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!int" || err != nil || value < 1 {
+		return 0, p.errorf(node, path, "must be a positive integer")
+	}
+	return value, nil
+}
+
+func (p configParser) oneOf(node *yaml.Node, path string, values []string) (string, error) {
+	for _, value := range values {
+		if node.Kind == yaml.ScalarNode && node.Value == value {
+			return value, nil
+		}
+	}
+	return "", p.errorf(node, path, "must be one of %s", strings.Join(values, ", "))
+}
+
+func (p configParser) colour(node *yaml.Node, path string) (string, error) {
+	if node.Tag == "!!null" {
+		// An unquoted #rrggbb value is parsed as a comment
+		return "", p.errorf(node, path, "must be a hex colour, quote it as \"#rrggbb\"")
+	}
+	if node.Kind != yaml.ScalarNode || !hexColourPattern.MatchString(node.Value) {
+		return "", p.errorf(node, path, "must be a hex colour like \"#0969da\", got %q", node.Value)
+	}
+	return node.Value, nil
+}
+
+// joinKey returns the dotted path of a key in the config file
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayKey returns the path used in errors about the document root
+func displayKey(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
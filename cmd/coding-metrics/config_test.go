@@ -0,0 +1,135 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JackPlowman/coding-metrics/render"
+)
+
+func TestParseConfigReadsRenderThemeAndOutputs(t *testing.T) {
+	content := `
+colour_profile: dark
+render:
+  sections: [stats, calendar]
+  options:
+    languages:
+      limit: 5
+      other_after: 4
+    calendar:
+      style: grid
+    stats:
+      lines: [commits, issues]
+theme:
+  background: "#000000"
+  contribution_levels: ["#111111", "#222222"]
+  language_colours:
+    Templ: "#333333"
+outputs:
+  - file_name: metrics-light.svg
+    colour_profile: default
+    size: compact
+    theme:
+      accent_primary: "#ff0000"
+  - file_name: languages.svg
+    section: languages
+`
+	config, err := parseConfig("coding-metrics.yml", []byte(content))
+	if err != nil {
+		t.Fatalf("expected config to be parsed, got %v", err)
+	}
+
+	if got := config.Inputs["colour_profile"]; got != "dark" {
+		t.Fatalf("expected colour_profile input, got %q", got)
+	}
+	wantRender := render.Options{
+		Sections:            []string{render.SectionStats, render.SectionCalendar},
+		LanguageLimit:       5,
+		OtherLanguagesAfter: 4,
+		CalendarStyle:       render.CalendarStyleGrid,
+		StatLines:           []string{"commits", "issues"},
+	}
+	if !reflect.DeepEqual(config.Render, wantRender) {
+		t.Fatalf("expected render options %+v, got %+v", wantRender, config.Render)
+	}
+	if config.Theme.Background != "#000000" || len(config.Theme.ContributionLevels) != 2 ||
+		config.Theme.LanguageColours["Templ"] != "#333333" {
+		t.Fatalf("expected theme overrides, got %+v", config.Theme)
+	}
+	if len(config.Outputs) != 2 || config.Outputs[0].FileName != "metrics-light.svg" ||
+		config.Outputs[0].Theme.AccentPrimary != "#ff0000" ||
+		config.Outputs[0].Size != render.SizeCompact {
+		t.Fatalf("expected an additional output, got %+v", config.Outputs)
+	}
+	if config.Outputs[1].Section != render.SectionLanguages {
+		t.Fatalf("expected a section output, got %+v", config.Outputs[1])
+	}
+}
+
+func TestParseConfigErrorsPointAtOffendingKey(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"colour_profil: dark\n", "coding-metrics.yml:1:1: colour_profil: unknown key"},
+		{
+			"colour_profile: neon\n",
+			"coding-metrics.yml:1:17: colour_profile: unknown colour profile",
+		},
+		{
+			"card_size: huge\n",
+			"coding-metrics.yml:1:12: card_size: must be one of compact, standard, wide",
+		},
+		{
+			"language_chart: radar\n",
+			"coding-metrics.yml:1:17: language_chart: must be one of bar, donut, pie, bars",
+		},
+		{
+			"theme:\n  language_colours:\n    Go: blue\n",
+			"coding-metrics.yml:3:9: theme.language_colours.Go: must be a hex colour like \"#0969da\", got \"blue\"",
+		},
+		{
+			"render:\n  sections: [profile, langs]\n",
+			"coding-metrics.yml:2:23: render.sections[1]: unknown section \"langs\"",
+		},
+		{
+			"render:\n  options:\n    languages:\n      limit: -1\n",
+			"coding-metrics.yml:4:14: render.options.languages.limit: must be a positive integer",
+		},
+		{
+			"render:\n  options:\n    calendar:\n      colour: red\n",
+			"coding-metrics.yml:4:7: render.options.calendar.colour: unknown key for the calendar section",
+		},
+		{
+			"theme:\n  background: #000000\n",
+			"coding-metrics.yml:2:14: theme.background: must be a hex colour, quote it",
+		},
+		{
+			"outputs:\n  - file_name: a.svg\n    section: langs\n",
+			"coding-metrics.yml:3:14: outputs[0].section: unknown section \"langs\"",
+		},
+		{
+			"outputs:\n  - colour_profile: dark\n",
+			"coding-metrics.yml:2:5: outputs[0]: file_name is required",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := parseConfig("coding-metrics.yml", []byte(test.content))
+		if err == nil || !strings.HasPrefix(err.Error(), test.want) {
+			t.Fatalf("expected error starting with %q for %q, got %v", test.want, test.content, err)
+		}
+	}
+}
+
+func TestLoadConfigIgnoresMissingDefaultFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yml")
+	if _, err := loadConfig(path, false); err != nil {
+		t.Fatalf("expected missing default config file to be ignored, got %v", err)
+	}
+	if _, err := loadConfig(path, true); err == nil {
+		t.Fatalf("expected missing explicit config file to return an error")
+	}
+}
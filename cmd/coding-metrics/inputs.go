@@ -0,0 +1,205 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/JackPlowman/coding-metrics/publish"
+	"github.com/JackPlowman/coding-metrics/render"
+)
+
+const defaultConfigFile = "coding-metrics.yml"
+
+// inputDefinition describes an input that can be set by a command line flag,
+// an INPUT_* environment variable or the config file.
+type inputDefinition struct {
+	Name    string
+	Default string
+	Usage   string
+}
+
+// inputDefinitions lists every input, matching the inputs in action.yml
+var inputDefinitions = []inputDefinition{
+	{Name: "github_token", Usage: "GitHub token used to fetch metrics"},
+	{
+		Name:    "data_source",
+		Default: "github",
+		Usage:   "where the metrics are collected from (github, local, gitlab, gitea)",
+	},
+	{Name: "local_repositories", Usage: "comma separated paths of the local git repositories"},
+	{Name: "local_author_emails", Usage: "comma separated emails of the user's commits"},
+	{Name: "local_login", Usage: "login of the user of the local git repositories"},
+	{Name: "gitlab_url", Default: "https://gitlab.com", Usage: "URL of the GitLab instance"},
+	{Name: "gitlab_token", Usage: "GitLab token used to fetch metrics"},
+	{Name: "gitea_url", Usage: "URL of the Gitea or Forgejo instance"},
+	{Name: "gitea_token", Usage: "Gitea or Forgejo token used to fetch metrics"},
+	{Name: "workflow_github_token", Usage: "GitHub token used to publish the SVG"},
+	{Name: "debug", Default: "false", Usage: "enable debug logging"},
+	{Name: "test_mode", Default: "false", Usage: "skip publishing the SVG"},
+	{Name: "repository", Usage: "repository to publish to (owner/repo)"},
+	{Name: "output_branch", Default: "main", Usage: "branch to commit the SVG to"},
+	{Name: "output_file_name", Default: "output.svg", Usage: "name of the output file"},
+	{Name: "commit_message", Default: "Update Coding Metrics", Usage: "commit message"},
+	{Name: "colour_profile", Default: "default", Usage: "colour profile to use"},
+	{Name: "card_size", Default: "standard", Usage: "card size (compact, standard, wide)"},
+	{
+		Name:    "language_chart",
+		Default: render.LanguageChartBar,
+		Usage:   "chart of the languages (bar, donut, pie, bars)",
+	},
+	{Name: "output_section", Usage: "render only this section instead of the card"},
+	{Name: "languages_exclude", Usage: "comma separated languages left out of the languages"},
+	{Name: "languages_aliases", Usage: "comma separated Alias=Language pairs merging languages"},
+	{
+		Name:    "languages_threshold",
+		Default: "1",
+		Usage:   "minimum percentage of the total size a language needs to be listed",
+	},
+	{Name: "languages_top", Usage: "maximum number of languages listed, all by default"},
+	{
+		Name:    "languages_authored",
+		Default: "false",
+		Usage:   "compute the languages from the lines the user added in their commits",
+	},
+	{
+		Name:  "languages_authored_commits",
+		Usage: "maximum number of the most recent commits walked for the authored languages",
+	},
+	{
+		Name:    "language_weighting",
+		Default: "bytes",
+		Usage:   "what the language percentages are shares of (bytes, repos, recency, commits)",
+	},
+	{
+		Name:  "repositories_affiliations",
+		Usage: "comma separated affiliations of the repositories aggregated (owner, organization_member, collaborator)",
+	},
+	{Name: "repositories_exclude_forks", Default: "false", Usage: "leave out forks"},
+	{
+		Name:    "repositories_exclude_archived",
+		Default: "false",
+		Usage:   "leave out archived repositories",
+	},
+	{
+		Name:    "repositories_exclude_private",
+		Default: "false",
+		Usage:   "leave out private repositories",
+	},
+	{
+		Name:  "repositories_exclude_owners",
+		Usage: "comma separated owners whose repositories are left out",
+	},
+	{Name: "repositories_exclude", Usage: "comma separated name globs of repositories left out"},
+	{Name: "repositories_exclude_topics", Usage: "comma separated topics of repositories left out"},
+	{Name: "publish_target", Default: "repository", Usage: "where to publish the SVG"},
+	{Name: "gist_id", Usage: "gist to publish the SVG to"},
+	{Name: "release_tag", Default: publish.DefaultReleaseTag, Usage: "tag of the rolling release"},
+	{Name: "readme_file", Usage: "Markdown file to update between the coding-metrics markers"},
+	{Name: "readme_dark_image", Usage: "image used for the dark colour scheme in the README"},
+	{Name: "readme_stats_table", Default: "false", Usage: "add a stats table to the README"},
+	{Name: "commit_author_name", Usage: "name of the commit author"},
+	{Name: "commit_author_email", Usage: "email of the commit author"},
+	{Name: "commit_committer_name", Usage: "name of the committer"},
+	{Name: "commit_committer_email", Usage: "email of the committer"},
+	{Name: "commit_trailers", Usage: "newline separated trailers appended to the commit message"},
+	{Name: "commit_signing_key", Usage: "armored GPG private key used to sign commits"},
+	{Name: "commit_signing_passphrase", Usage: "passphrase for the signing key"},
+}
+
+// inputSource resolves input values from flags, the environment and the
+// config file, in that order of precedence.
+type inputSource struct {
+	flags  map[string]string
+	config map[string]string
+}
+
+// Global inputs - will be set in main from the command line and config file
+var currentInputs = inputSource{}
+
+// getInput returns the value of the named input. Command line flags take
+// precedence over INPUT_* environment variables, which take precedence over
+// the config file. Empty values are treated as unset.
+func getInput(name string) string {
+	return currentInputs.get(name)
+}
+
+func (s inputSource) get(name string) string {
+	if value := s.flags[name]; value != "" {
+		return value
+	}
+	if value := os.Getenv(inputEnvName(name)); value != "" {
+		return value
+	}
+	if value := s.config[name]; value != "" {
+		return value
+	}
+	for _, definition := range inputDefinitions {
+		if definition.Name == name {
+			return definition.Default
+		}
+	}
+	return ""
+}
+
+// inputEnvName returns the GitHub Actions environment variable for an input
+func inputEnvName(name string) string {
+	return "INPUT_" + strings.ToUpper(name)
+}
+
+// inputFlagName returns the command line flag for an input
+func inputFlagName(name string) string {
+	return strings.ReplaceAll(name, "_", "-")
+}
+
+// registerInputFlags adds a flag for every input to the flag set
+func registerInputFlags(flags *flag.FlagSet) {
+	for _, definition := range inputDefinitions {
+		usage := fmt.Sprintf("%s (env %s)", definition.Usage, inputEnvName(definition.Name))
+		flags.String(inputFlagName(definition.Name), "", usage)
+	}
+}
+
+// parsedInputFlags returns the inputs that were set on the command line
+func parsedInputFlags(flags *flag.FlagSet) map[string]string {
+	set := map[string]string{}
+	flags.Visit(func(f *flag.Flag) {
+		name := strings.ReplaceAll(f.Name, "-", "_")
+		if isInput(name) {
+			set[name] = f.Value.String()
+		}
+	})
+	return set
+}
+
+// isInput reports whether name is a known input
+func isInput(name string) bool {
+	for _, definition := range inputDefinitions {
+		if definition.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// initInputs sets the global inputs and config from the parsed command line
+// flags and the config file. The config file path is taken from configPath,
+// then INPUT_CONFIG_FILE, falling back to coding-metrics.yml if it exists.
+func initInputs(flags *flag.FlagSet, configPath string) error {
+	if configPath == "" {
+		configPath = os.Getenv("INPUT_CONFIG_FILE")
+	}
+	explicit := configPath != ""
+	if !explicit {
+		configPath = defaultConfigFile
+	}
+
+	config, err := loadConfig(configPath, explicit)
+	if err != nil {
+		return err
+	}
+	currentConfig = config
+	currentInputs = inputSource{flags: parsedInputFlags(flags), config: config.Inputs}
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/JackPlowman/coding-metrics/gitea"
+	"github.com/JackPlowman/coding-metrics/gitlab"
+	"github.com/JackPlowman/coding-metrics/localgit"
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+func TestInputSourcePrecedence(t *testing.T) {
+	t.Setenv("INPUT_COLOUR_PROFILE", "ocean")
+	t.Setenv("INPUT_OUTPUT_BRANCH", "")
+	source := inputSource{
+		flags:  map[string]string{"colour_profile": "dark"},
+		config: map[string]string{"colour_profile": "sunset", "output_branch": "metrics"},
+	}
+
+	if got := source.get("colour_profile"); got != "dark" {
+		t.Fatalf("expected flag to take precedence, got %q", got)
+	}
+	delete(source.flags, "colour_profile")
+	if got := source.get("colour_profile"); got != "ocean" {
+		t.Fatalf("expected environment to take precedence over config, got %q", got)
+	}
+	if got := source.get("output_branch"); got != "metrics" {
+		t.Fatalf("expected empty environment variable to fall back to config, got %q", got)
+	}
+	if got := source.get("output_file_name"); got != "output.svg" {
+		t.Fatalf("expected default value, got %q", got)
+	}
+}
+
+func TestInitInputsReadsFlagsAndConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coding-metrics.yml")
+	config := "colour_profile: sunset\nreadme_stats_table: true\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("INPUT_COLOUR_PROFILE", "")
+	t.Setenv("INPUT_README_STATS_TABLE", "")
+	t.Setenv("INPUT_OUTPUT_BRANCH", "")
+	t.Cleanup(func() { currentInputs = inputSource{} })
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	registerInputFlags(flags)
+	if err := flags.Parse([]string{"--output-branch", "metrics"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	if err := initInputs(flags, path); err != nil {
+		t.Fatalf("expected inputs to be initialized, got %v", err)
+	}
+
+	if got := getInput("colour_profile"); got != "sunset" {
+		t.Fatalf("expected colour profile from config, got %q", got)
+	}
+	if got := getInput("readme_stats_table"); got != "true" {
+		t.Fatalf("expected boolean config value as string, got %q", got)
+	}
+	if got := getInput("output_branch"); got != "metrics" {
+		t.Fatalf("expected output branch from flag, got %q", got)
+	}
+}
+
+func TestNewCollectorReadsLanguageInputs(t *testing.T) {
+	t.Setenv("INPUT_LANGUAGES_EXCLUDE", "HTML, CSS")
+	t.Setenv("INPUT_LANGUAGES_ALIASES", "TSX=TypeScript")
+	t.Setenv("INPUT_LANGUAGES_THRESHOLD", "0.5")
+	t.Setenv("INPUT_LANGUAGES_TOP", "8")
+	t.Setenv("INPUT_LANGUAGE_WEIGHTING", "recency")
+	t.Setenv("INPUT_LANGUAGES_AUTHORED", "true")
+	t.Setenv("INPUT_LANGUAGES_AUTHORED_COMMITS", "50")
+
+	collector, err := newCollector()
+	if err != nil {
+		t.Fatalf("expected a collector, got %v", err)
+	}
+	want := metrics.LanguageOptions{
+		Exclude:         []string{"HTML", "CSS"},
+		Aliases:         map[string]string{"TSX": "TypeScript"},
+		Threshold:       0.5,
+		Top:             8,
+		Weighting:       metrics.WeightingRecency,
+		Authored:        true,
+		AuthoredCommits: 50,
+	}
+	if !reflect.DeepEqual(collector.Languages, want) {
+		t.Fatalf("expected language options %+v, got %+v", want, collector.Languages)
+	}
+
+	t.Setenv("INPUT_LANGUAGE_WEIGHTING", "lines")
+	if _, err := newCollector(); err == nil {
+		t.Fatalf("expected an error for an unknown weighting")
+	}
+	t.Setenv("INPUT_LANGUAGE_WEIGHTING", "")
+	t.Setenv("INPUT_LANGUAGES_THRESHOLD", "lots")
+	if _, err := newCollector(); err == nil {
+		t.Fatalf("expected an error for an invalid threshold")
+	}
+}
+
+func TestNewCollectorReadsRepositoryInputs(t *testing.T) {
+	t.Setenv("INPUT_REPOSITORIES_AFFILIATIONS", "owner, organization_member")
+	t.Setenv("INPUT_REPOSITORIES_EXCLUDE_FORKS", "true")
+	t.Setenv("INPUT_REPOSITORIES_EXCLUDE", "*-mirror")
+	t.Setenv("INPUT_REPOSITORIES_EXCLUDE_TOPICS", "vendored")
+
+	collector, err := newCollector()
+	if err != nil {
+		t.Fatalf("expected a collector, got %v", err)
+	}
+	want := metrics.RepositoryFilter{
+		Affiliations:  []string{metrics.AffiliationOwner, metrics.AffiliationOrganizationMember},
+		ExcludeForks:  true,
+		ExcludeOwners: []string{},
+		ExcludeNames:  []string{"*-mirror"},
+		ExcludeTopics: []string{"vendored"},
+	}
+	if !reflect.DeepEqual(collector.Repositories, want) {
+		t.Fatalf("expected repository filter %+v, got %+v", want, collector.Repositories)
+	}
+
+	t.Setenv("INPUT_REPOSITORIES_AFFILIATIONS", "friend")
+	if _, err := newCollector(); err == nil {
+		t.Fatalf("expected an error for an unknown affiliation")
+	}
+}
+
+func TestNewProviderSelectsDataSource(t *testing.T) {
+	t.Setenv("INPUT_DATA_SOURCE", "local")
+	t.Setenv("INPUT_LOCAL_REPOSITORIES", "../app, ../lib")
+	t.Setenv("INPUT_LOCAL_AUTHOR_EMAILS", "octocat@example.com")
+	t.Setenv("INPUT_LOCAL_LOGIN", "octocat")
+	t.Setenv("INPUT_LANGUAGES_TOP", "3")
+
+	provider, err := newProvider()
+	if err != nil {
+		t.Fatalf("expected a provider, got %v", err)
+	}
+	collector, ok := provider.(*localgit.Collector)
+	if !ok {
+		t.Fatalf("expected a local git collector, got %T", provider)
+	}
+	if !reflect.DeepEqual(collector.Repositories, []string{"../app", "../lib"}) ||
+		!reflect.DeepEqual(collector.Authors, []string{"octocat@example.com"}) ||
+		collector.Login != "octocat" || collector.Languages.Top != 3 {
+		t.Fatalf("expected the local inputs, got %+v", collector)
+	}
+
+	t.Setenv("INPUT_DATA_SOURCE", "gitlab")
+	t.Setenv("INPUT_GITLAB_URL", "https://gitlab.example.com/")
+	t.Setenv("INPUT_GITLAB_TOKEN", "glpat-token")
+	t.Setenv("INPUT_REPOSITORIES_EXCLUDE_FORKS", "true")
+	provider, err = newProvider()
+	if err != nil {
+		t.Fatalf("expected a provider, got %v", err)
+	}
+	gitLabCollector, ok := provider.(*gitlab.Collector)
+	if !ok {
+		t.Fatalf("expected a GitLab collector, got %T", provider)
+	}
+	if gitLabCollector.API.Endpoint != "https://gitlab.example.com/api/v4" ||
+		gitLabCollector.API.Token != "glpat-token" ||
+		!gitLabCollector.Repositories.ExcludeForks {
+		t.Fatalf("expected the GitLab inputs, got %+v", gitLabCollector)
+	}
+	t.Setenv("INPUT_REPOSITORIES_AFFILIATIONS", "owner")
+	if _, err := newProvider(); err == nil {
+		t.Fatalf("expected an error for affiliations of GitLab projects")
+	}
+	t.Setenv("INPUT_REPOSITORIES_AFFILIATIONS", "")
+
+	t.Setenv("INPUT_DATA_SOURCE", "gitea")
+	if _, err := newProvider(); err == nil {
+		t.Fatalf("expected an error without a Gitea instance")
+	}
+	t.Setenv("INPUT_GITEA_URL", "https://codeberg.org/")
+	t.Setenv("INPUT_GITEA_TOKEN", "gitea-token")
+	provider, err = newProvider()
+	if err != nil {
+		t.Fatalf("expected a provider, got %v", err)
+	}
+	giteaCollector, ok := provider.(*gitea.Collector)
+	if !ok {
+		t.Fatalf("expected a Gitea collector, got %T", provider)
+	}
+	if giteaCollector.API.Endpoint != "https://codeberg.org/api/v1" ||
+		giteaCollector.API.Token != "gitea-token" ||
+		!giteaCollector.Repositories.ExcludeForks {
+		t.Fatalf("expected the Gitea inputs, got %+v", giteaCollector)
+	}
+	t.Setenv("INPUT_REPOSITORIES_AFFILIATIONS", "collaborator")
+	if _, err := newProvider(); err == nil {
+		t.Fatalf("expected an error for affiliations of Gitea repositories")
+	}
+	t.Setenv("INPUT_REPOSITORIES_AFFILIATIONS", "")
+
+	t.Setenv("INPUT_DATA_SOURCE", "github")
+	if provider, err := newProvider(); err != nil {
+		t.Fatalf("expected a provider, got %v", err)
+	} else if _, ok := provider.(*metrics.Collector); !ok {
+		t.Fatalf("expected a GitHub collector, got %T", provider)
+	}
+
+	t.Setenv("INPUT_DATA_SOURCE", "svn")
+	if _, err := newProvider(); err == nil {
+		t.Fatalf("expected an error for an unknown data source")
+	}
+}
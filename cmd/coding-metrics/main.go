@@ -0,0 +1,302 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	svg "github.com/twpayne/go-svg"
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/gitea"
+	"github.com/JackPlowman/coding-metrics/gitlab"
+	"github.com/JackPlowman/coding-metrics/localgit"
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/render"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+// initialize global logger
+func init() {
+	logger, err := initLogger()
+	zap.ReplaceGlobals(zap.Must(logger, err))
+}
+
+// newRenderer returns a renderer using the colour profile selected by the
+// colour_profile input, with the theme overrides and render options of the
+// config file
+func newRenderer() *render.Renderer {
+	return newOutputRenderer(outputConfig{})
+}
+
+// newOutputRenderer returns a renderer for an output of the config file. The
+// output's colour profile replaces the colour_profile input and the top level
+// theme overrides, and its theme overrides are applied last. Its size replaces
+// the card_size input. The language_chart input sets the chart of the
+// languages.
+func newOutputRenderer(output outputConfig) *render.Renderer {
+	profile := theme.GetColourProfile(getInput("colour_profile")).WithOverrides(currentConfig.Theme)
+	if output.ColourProfile != "" {
+		profile = theme.GetColourProfile(output.ColourProfile)
+	}
+	renderer := render.New(profile.WithOverrides(output.Theme))
+	renderer.Options = currentConfig.Render
+	renderer.Options.Size = getInput("card_size")
+	if output.Size != "" {
+		renderer.Options.Size = output.Size
+	}
+	if !render.IsSize(renderer.Options.Size) {
+		zap.L().Fatal("Unknown card size", zap.String("card_size", renderer.Options.Size))
+	}
+	renderer.Options.LanguageChart = getInput("language_chart")
+	if !render.IsLanguageChart(renderer.Options.LanguageChart) {
+		zap.L().Fatal(
+			"Unknown language chart",
+			zap.String("language_chart", renderer.Options.LanguageChart),
+		)
+	}
+	return renderer
+}
+
+// Data sources selected by the data_source input
+const (
+	dataSourceGitHub = "github"
+	dataSourceLocal  = "local"
+	dataSourceGitLab = "gitlab"
+	dataSourceGitea  = "gitea"
+)
+
+// dataSources lists the supported data sources
+var dataSources = []string{dataSourceGitHub, dataSourceLocal, dataSourceGitLab, dataSourceGitea}
+
+// newProvider returns the provider of the data source selected by the
+// data_source input
+func newProvider() (metrics.Provider, error) {
+	switch source := getInput("data_source"); source {
+	case dataSourceGitHub:
+		collector, err := newCollector()
+		if err != nil {
+			return nil, err
+		}
+		return collector, nil
+	case dataSourceLocal:
+		collector, err := newLocalCollector()
+		if err != nil {
+			return nil, err
+		}
+		return collector, nil
+	case dataSourceGitLab:
+		collector, err := newGitLabCollector()
+		if err != nil {
+			return nil, err
+		}
+		return collector, nil
+	case dataSourceGitea:
+		collector, err := newGiteaCollector()
+		if err != nil {
+			return nil, err
+		}
+		return collector, nil
+	default:
+		return nil, fmt.Errorf(
+			"unknown data_source %q, expected one of %s",
+			source,
+			strings.Join(dataSources, ", "),
+		)
+	}
+}
+
+// newCollector returns a collector authenticated with the github_token input,
+// aggregating languages as configured by the languages_* inputs over the
+// repositories selected by the repositories_* inputs
+func newCollector() (*metrics.Collector, error) {
+	collector := metrics.NewCollector(getInput("github_token"))
+	languages, err := languageOptions()
+	if err != nil {
+		return nil, err
+	}
+	collector.Languages = languages
+	collector.Repositories, err = repositoryFilter()
+	if err != nil {
+		return nil, err
+	}
+	return collector, nil
+}
+
+// repositoryFilter returns the repository filter set by the repositories_*
+// inputs
+func repositoryFilter() (metrics.RepositoryFilter, error) {
+	filter := metrics.RepositoryFilter{
+		Affiliations:    metrics.ParseList(strings.ToUpper(getInput("repositories_affiliations"))),
+		ExcludeForks:    getInput("repositories_exclude_forks") == "true",
+		ExcludeArchived: getInput("repositories_exclude_archived") == "true",
+		ExcludePrivate:  getInput("repositories_exclude_private") == "true",
+		ExcludeOwners:   metrics.ParseList(getInput("repositories_exclude_owners")),
+		ExcludeNames:    metrics.ParseList(getInput("repositories_exclude")),
+		ExcludeTopics:   metrics.ParseList(getInput("repositories_exclude_topics")),
+	}
+	if err := filter.Validate(); err != nil {
+		return metrics.RepositoryFilter{}, err
+	}
+	return filter, nil
+}
+
+// otherRepositoryFilter returns the repository filter of a data source other
+// than GitHub, which has no affiliations
+func otherRepositoryFilter(source string) (metrics.RepositoryFilter, error) {
+	filter, err := repositoryFilter()
+	if err != nil {
+		return metrics.RepositoryFilter{}, err
+	}
+	if len(filter.Affiliations) > 0 {
+		return metrics.RepositoryFilter{}, fmt.Errorf(
+			"repositories_affiliations is not supported by the %s data source",
+			source,
+		)
+	}
+	return filter, nil
+}
+
+// languageOptions returns the language options set by the languages_*
+// inputs
+func languageOptions() (metrics.LanguageOptions, error) {
+	aliases, err := metrics.ParseLanguageAliases(getInput("languages_aliases"))
+	if err != nil {
+		return metrics.LanguageOptions{}, err
+	}
+	threshold, err := strconv.ParseFloat(getInput("languages_threshold"), 64)
+	if err != nil || threshold < 0 || threshold > 100 {
+		return metrics.LanguageOptions{}, fmt.Errorf(
+			"invalid languages_threshold %q, expected a percentage",
+			getInput("languages_threshold"),
+		)
+	}
+	top, err := optionalCount("languages_top")
+	if err != nil {
+		return metrics.LanguageOptions{}, err
+	}
+	authoredCommits, err := optionalCount("languages_authored_commits")
+	if err != nil {
+		return metrics.LanguageOptions{}, err
+	}
+	options := metrics.LanguageOptions{
+		Exclude:         metrics.ParseList(getInput("languages_exclude")),
+		Aliases:         aliases,
+		Threshold:       threshold,
+		Top:             top,
+		Weighting:       getInput("language_weighting"),
+		Authored:        getInput("languages_authored") == "true",
+		AuthoredCommits: authoredCommits,
+	}
+	if err := options.Validate(); err != nil {
+		return metrics.LanguageOptions{}, err
+	}
+	return options, nil
+}
+
+// newLocalCollector returns a collector reading the local git repositories
+// set by the local_* inputs
+func newLocalCollector() (*localgit.Collector, error) {
+	collector := localgit.NewCollector(metrics.ParseList(getInput("local_repositories"))...)
+	collector.Authors = metrics.ParseList(getInput("local_author_emails"))
+	collector.Login = getInput("local_login")
+	languages, err := languageOptions()
+	if err != nil {
+		return nil, err
+	}
+	collector.Languages = languages
+	return collector, nil
+}
+
+// newGitLabCollector returns a collector for the GitLab instance at the
+// gitlab_url input, authenticated with the gitlab_token input
+func newGitLabCollector() (*gitlab.Collector, error) {
+	collector := gitlab.NewCollector(getInput("gitlab_token"))
+	collector.API.Endpoint = strings.TrimSuffix(getInput("gitlab_url"), "/") + "/api/v4"
+	repositories, err := otherRepositoryFilter(dataSourceGitLab)
+	if err != nil {
+		return nil, err
+	}
+	collector.Repositories = repositories
+	languages, err := languageOptions()
+	if err != nil {
+		return nil, err
+	}
+	collector.Languages = languages
+	return collector, nil
+}
+
+// newGiteaCollector returns a collector for the Gitea or Forgejo instance at
+// the gitea_url input, authenticated with the gitea_token input
+func newGiteaCollector() (*gitea.Collector, error) {
+	instance := strings.TrimSuffix(getInput("gitea_url"), "/")
+	if instance == "" {
+		return nil, errors.New("gitea_url is required for the gitea data source")
+	}
+	collector := gitea.NewCollector(instance+"/api/v1", getInput("gitea_token"))
+	repositories, err := otherRepositoryFilter(dataSourceGitea)
+	if err != nil {
+		return nil, err
+	}
+	collector.Repositories = repositories
+	languages, err := languageOptions()
+	if err != nil {
+		return nil, err
+	}
+	collector.Languages = languages
+	return collector, nil
+}
+
+// optionalCount returns the value of the named input as a count, 0 if unset
+func optionalCount(name string) (int, error) {
+	value := getInput(name)
+	if value == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a positive integer", name, value)
+	}
+	return count, nil
+}
+
+// renderSVG renders the document with the renderer, as a standalone SVG of
+// the section if one is set
+func renderSVG(
+	renderer *render.Renderer,
+	document *metrics.Metrics,
+	section string,
+) (*svg.SVGElement, error) {
+	if section == "" {
+		return renderer.Render(document), nil
+	}
+	element, err := renderer.RenderSection(document, section)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the %s section: %w", section, err)
+	}
+	return element, nil
+}
+
+// initLogger initializes and returns a zap logger according to the
+// debug input. If debug=="true" a development logger
+// will be returned, otherwise a production logger is used.
+func initLogger() (*zap.Logger, error) {
+	if getInput("debug") == "true" {
+		return zap.NewDevelopment()
+	}
+	return zap.NewProduction()
+}
+
+// main is the entry point for the application.
+func main() {
+	if err := runCLI(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/publish"
+	"github.com/JackPlowman/coding-metrics/render"
+)
+
+// actionOutput is a single GitHub Actions step output
+type actionOutput struct {
+	Name  string
+	Value string
+}
+
+// writeActionOutputs writes the step outputs to GITHUB_OUTPUT and a Markdown
+// job summary to GITHUB_STEP_SUMMARY. Either is skipped when its environment
+// variable is not set, e.g. when running outside of GitHub Actions.
+func writeActionOutputs(file *os.File, result publish.Result, stats *metrics.GitHubTotalsStats) {
+	outputs := buildActionOutputs(workspacePath(file.Name()), result, stats)
+	if outputPath := os.Getenv("GITHUB_OUTPUT"); outputPath != "" {
+		if err := appendToFile(outputPath, formatActionOutputs(outputs)); err != nil {
+			zap.L().Error("Failed to write step outputs", zap.Error(err))
+		}
+	}
+
+	if summaryPath := os.Getenv("GITHUB_STEP_SUMMARY"); summaryPath != "" {
+		if err := appendToFile(summaryPath, buildStepSummary(result.URL, stats)); err != nil {
+			zap.L().Error("Failed to write job summary", zap.Error(err))
+		}
+	}
+}
+
+// workspacePath returns the path relative to the GitHub Actions workspace, as
+// the action runs in a container with the workspace mounted at another path
+// than in later steps. Paths outside of the workspace are returned as is.
+func workspacePath(path string) string {
+	workspace := os.Getenv("GITHUB_WORKSPACE")
+	if workspace == "" {
+		return path
+	}
+	relative, err := filepath.Rel(workspace, path)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, "../") {
+		return path
+	}
+	return relative
+}
+
+// buildActionOutputs returns the outputs declared in action.yml
+func buildActionOutputs(
+	svgPath string,
+	result publish.Result,
+	stats *metrics.GitHubTotalsStats,
+) []actionOutput {
+	outputs := []actionOutput{
+		{Name: "svg_path", Value: svgPath},
+		{Name: "commit_sha", Value: result.CommitSHA},
+		{Name: "changed", Value: strconv.FormatBool(result.Changed)},
+	}
+	if stats != nil {
+		outputs = append(outputs,
+			actionOutput{Name: "total_commits", Value: strconv.Itoa(stats.TotalCommits)},
+			actionOutput{Name: "total_pull_requests", Value: strconv.Itoa(stats.TotalPullRequests)},
+			actionOutput{
+				Name:  "total_pull_request_reviews",
+				Value: strconv.Itoa(stats.TotalPullRequestReviews),
+			},
+			actionOutput{Name: "total_issues", Value: strconv.Itoa(stats.TotalIssues)},
+			actionOutput{Name: "total_starred_repos", Value: strconv.Itoa(stats.TotalStarredRepos)},
+			actionOutput{Name: "total_sponsors", Value: strconv.Itoa(stats.TotalSponsors)},
+		)
+	}
+	return outputs
+}
+
+// formatActionOutputs formats outputs in the GITHUB_OUTPUT file syntax, using
+// a heredoc delimiter for multi-line values.
+func formatActionOutputs(outputs []actionOutput) string {
+	var b strings.Builder
+	for _, output := range outputs {
+		if !strings.Contains(output.Value, "\n") {
+			fmt.Fprintf(&b, "%s=%s\n", output.Name, output.Value)
+			continue
+		}
+		delimiter := "CODING_METRICS_EOF"
+		for strings.Contains(output.Value, delimiter) {
+			delimiter += "_"
+		}
+		fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", output.Name, delimiter, output.Value, delimiter)
+	}
+	return b.String()
+}
+
+// buildStepSummary builds the Markdown job summary with a preview of the SVG
+// at the URL it was published to and a table of the stats. The preview is left
+// out when nothing was published, e.g. in test mode, as GitHub strips data
+// URIs from the summary.
+func buildStepSummary(svgURL string, stats *metrics.GitHubTotalsStats) string {
+	var b strings.Builder
+	b.WriteString("## Coding Metrics\n\n")
+	if svgURL != "" {
+		fmt.Fprintf(&b, "![%s](%s)\n\n", render.Description, svgURL)
+	}
+	if stats != nil {
+		b.WriteString(publish.StatsMarkdownTable(stats))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// appendToFile appends the content to the file at path, creating it if needed
+func appendToFile(path, content string) error {
+	// #nosec G304 -- The path is provided by the GitHub Actions runner.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
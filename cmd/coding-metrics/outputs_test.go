@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	svg "github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/publish"
+)
+
+func TestFormatActionOutputsUsesDelimiterForMultiline(t *testing.T) {
+	got := formatActionOutputs([]actionOutput{
+		{Name: "changed", Value: "true"},
+		{Name: "summary", Value: "line one\nline two"},
+	})
+
+	want := "changed=true\nsummary<<CODING_METRICS_EOF\nline one\nline two\nCODING_METRICS_EOF\n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestWriteActionOutputsWritesOutputsAndSummary(t *testing.T) {
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output")
+	summaryPath := filepath.Join(dir, "summary")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+	t.Setenv("GITHUB_WORKSPACE", dir)
+
+	svgPath := filepath.Join(dir, "output.svg")
+	if err := os.WriteFile(svgPath, []byte("<svg/>"), 0o600); err != nil {
+		t.Fatalf("failed to write test SVG: %v", err)
+	}
+	file, err := os.Open(svgPath)
+	if err != nil {
+		t.Fatalf("failed to open test SVG: %v", err)
+	}
+	t.Cleanup(func() { _ = file.Close() })
+
+	writeActionOutputs(
+		file,
+		publish.Result{CommitSHA: "abc123", Changed: true, URL: "https://example.com/output.svg"},
+		&metrics.GitHubTotalsStats{TotalCommits: 42},
+	)
+
+	outputs, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected outputs file to be written, got %v", err)
+	}
+	for _, want := range []string{"svg_path=output.svg", "commit_sha=abc123", "changed=true", "total_commits=42"} {
+		if !strings.Contains(string(outputs), want+"\n") {
+			t.Fatalf("expected outputs to contain %q, got %q", want, string(outputs))
+		}
+	}
+
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("expected summary file to be written, got %v", err)
+	}
+	for _, want := range []string{"](https://example.com/output.svg)", "| Commits | 42 |"} {
+		if !strings.Contains(string(summary), want) {
+			t.Fatalf("expected summary to contain %q, got %q", want, string(summary))
+		}
+	}
+}
+
+func TestCreateLocalFileWritesToWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", dir)
+	t.Setenv("INPUT_OUTPUT_FILE_NAME", "../cards/metrics.svg")
+
+	file := createLocalFile(svg.New())
+
+	want := filepath.Join(dir, "cards", "metrics.svg")
+	if file.Name() != want {
+		t.Fatalf("expected the SVG to be written to %s, got %s", want, file.Name())
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("expected the SVG file to exist, got %v", err)
+	}
+	if got := workspacePath(file.Name()); got != filepath.Join("cards", "metrics.svg") {
+		t.Fatalf("expected the path relative to the workspace, got %s", got)
+	}
+}
+
+func TestWriteLocalOutputsKeepsDirectories(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", dir)
+
+	err := writeLocalOutputs([]publish.File{
+		{Name: "metrics.svg", Content: []byte("<svg/>")},
+		{Name: "cards/dark.svg", Content: []byte("<svg>dark</svg>")},
+	})
+	if err != nil {
+		t.Fatalf("expected the outputs to be written, got %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "cards", "dark.svg"))
+	if err != nil || string(content) != "<svg>dark</svg>" {
+		t.Fatalf("expected the output at its path in the workspace, got %q, %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "metrics.svg")); err == nil {
+		t.Fatalf("expected the main SVG to be left to createLocalFile")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/go-github/v61/github"
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/publish"
+	"github.com/JackPlowman/coding-metrics/render"
+)
+
+// publishSVG publishes the SVG files to the target selected by the
+// publish_target input. The first file is the main SVG, named by the
+// output_file_name input.
+func publishSVG(files []publish.File, stats *metrics.GitHubTotalsStats) publish.Result {
+	if getInput("test_mode") == "true" {
+		zap.L().Warn("Running in test mode")
+		return publish.Result{}
+	}
+
+	ctx := context.Background()
+	gh := publish.NewGitHubClient(ctx, getInput("workflow_github_token"))
+
+	var result publish.Result
+	var err error
+	switch target := getInput("publish_target"); target {
+	case "", "repository":
+		result, err = commitSVG(ctx, gh, files, stats)
+	case "gist":
+		result, err = publish.Gist(ctx, gh, getInput("gist_id"), baseNames(files))
+	case "release":
+		owner, repo := repositoryInput()
+		result, err = publish.Release(ctx, gh, baseNames(files), publish.ReleaseOptions{
+			Owner:  owner,
+			Repo:   repo,
+			Tag:    getInput("release_tag"),
+			Branch: getInput("output_branch"),
+		})
+	default:
+		zap.L().Fatal("Unknown publish target", zap.String("publish_target", target))
+		return publish.Result{}
+	}
+	if err != nil {
+		zap.L().Fatal("Failed to publish SVG file", zap.Error(err))
+	}
+	return result
+}
+
+// baseNames returns the files named without their directories, as gists and
+// releases have no directories
+func baseNames(files []publish.File) []publish.File {
+	named := make([]publish.File, 0, len(files))
+	for _, file := range files {
+		named = append(named, publish.File{Name: filepath.Base(file.Name), Content: file.Content})
+	}
+	return named
+}
+
+// commitSVG commits the SVGs to the repository input. If a README file is
+// configured, its coding-metrics block is updated in the same commit.
+func commitSVG(
+	ctx context.Context,
+	gh *github.Client,
+	files []publish.File,
+	stats *metrics.GitHubTotalsStats,
+) (publish.Result, error) {
+	owner, repo := repositoryInput()
+	trailers, err := publish.ParseCommitTrailers(getInput("commit_trailers"))
+	if err != nil {
+		return publish.Result{}, fmt.Errorf("invalid commit trailers: %w", err)
+	}
+	settings, err := loadCommitSettings()
+	if err != nil {
+		return publish.Result{}, fmt.Errorf("invalid commit settings: %w", err)
+	}
+
+	opts := publish.CommitOptions{
+		Owner:    owner,
+		Repo:     repo,
+		Branch:   getInput("output_branch"),
+		Message:  publish.AppendCommitTrailers(getInput("commit_message"), trailers),
+		Settings: settings,
+	}
+	if readmePath := getInput("readme_file"); readmePath != "" {
+		opts.Readme = &publish.ReadmeOptions{
+			Path:      readmePath,
+			DarkImage: getInput("readme_dark_image"),
+			Alt:       render.Description,
+		}
+		if getInput("readme_stats_table") == "true" {
+			opts.Readme.Stats = stats
+		}
+	}
+	return publish.Commit(ctx, gh, files, opts)
+}
+
+// repositoryInput returns the owner and name of the repository input
+func repositoryInput() (string, string) {
+	ownerRepo := getInput("repository")
+	owner, repo, ok := publish.SplitRepository(ownerRepo)
+	if !ok {
+		zap.L().Fatal("Invalid repository format", zap.String("repository", ownerRepo))
+	}
+	return owner, repo
+}
+
+// loadCommitSettings builds the commit settings from the commit_* inputs
+func loadCommitSettings() (publish.CommitSettings, error) {
+	return publish.NewCommitSettings(
+		publish.CommitIdentity(getInput("commit_author_name"), getInput("commit_author_email")),
+		publish.CommitIdentity(
+			getInput("commit_committer_name"),
+			getInput("commit_committer_email"),
+		),
+		getInput("commit_signing_key"),
+		getInput("commit_signing_passphrase"),
+	)
+}
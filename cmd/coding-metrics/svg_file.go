@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	svg "github.com/twpayne/go-svg"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/publish"
+)
+
+// createLocalFile writes the SVG to output_file_name in the GitHub Actions
+// workspace, so later steps of the job can read it. Outside of GitHub Actions
+// it is written to the system temp directory.
+func createLocalFile(
+	svgElement *svg.SVGElement,
+) *os.File {
+	if os.Getenv("GITHUB_WORKSPACE") == "" {
+		return createFile(svgElement, filepath.Join(os.TempDir(), filepath.Clean("output.svg")))
+	}
+	return createFile(svgElement, localPath(localDirectory(), getInput("output_file_name")))
+}
+
+// writeLocalOutputs writes the outputs of the config file, the files after
+// the main SVG, to their paths in the directory createLocalFile writes to
+func writeLocalOutputs(files []publish.File) error {
+	for _, file := range files[1:] {
+		path := localPath(localDirectory(), file.Name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return fmt.Errorf("failed to create the directory of %s: %w", file.Name, err)
+		}
+		zap.L().Info("Writing SVG to file", zap.String("path", path))
+		if err := os.WriteFile(path, file.Content, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// localDirectory returns the GitHub Actions workspace, or the system temp
+// directory outside of GitHub Actions
+func localDirectory() string {
+	if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+		return workspace
+	}
+	return os.TempDir()
+}
+
+// localPath returns the path of the file name, which may contain
+// directories, in dir. Rooting the name keeps it inside dir.
+func localPath(dir, name string) string {
+	return filepath.Join(dir, filepath.Clean("/"+name))
+}
+
+// createFile writes the SVG to the file at path, creating its directory, and
+// returns the closed file
+func createFile(svgElement *svg.SVGElement, path string) *os.File {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		zap.L().Fatal("Could not create SVG directory", zap.Error(err))
+	}
+	// #nosec G304 -- The file path is controlled and safe in this context.
+	file, err := os.Create(path)
+	if err != nil {
+		zap.L().Fatal("Could not create SVG file", zap.Error(err))
+	}
+	zap.L().Info("Writing SVG to file", zap.String("path", path))
+	if _, writeErr := svgElement.WriteTo(file); writeErr != nil {
+		zap.L().Fatal("Failed to write SVG to file", zap.Error(writeErr))
+	}
+	if closeErr := file.Close(); closeErr != nil {
+		zap.L().Error("Failed to close SVG file", zap.Error(closeErr))
+	}
+
+	return file
+}
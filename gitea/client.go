@@ -0,0 +1,45 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+)
+
+// Client provides a client for making GET requests to the REST API of a
+// Gitea or Forgejo instance. The Endpoint ends in /api/v1.
+type Client struct {
+	githubapi.RESTClient
+}
+
+// NewClient creates a new Gitea REST client for the API at the endpoint
+func NewClient(endpoint, token string) *Client {
+	client := &Client{RESTClient: *githubapi.NewRESTClient("Gitea", endpoint, token)}
+	client.TokenHeader = func(token string) (string, string) {
+		return "Authorization", "token " + token
+	}
+	return client
+}
+
+// Count returns the number of items of the list at the path from the
+// X-Total-Count header, requesting a single item
+func (c *Client) Count(ctx context.Context, path string) (int, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	var items []json.RawMessage
+	header, err := c.Get(ctx, path+separator+"limit=1", &items)
+	if err != nil {
+		return 0, err
+	}
+	total, err := strconv.Atoi(header.Get("X-Total-Count"))
+	if err != nil {
+		return 0, fmt.Errorf("missing the X-Total-Count header of %s", path)
+	}
+	return total, nil
+}
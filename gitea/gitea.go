@@ -0,0 +1,331 @@
+// Package gitea collects coding metrics from the REST API of a Gitea or
+// Forgejo instance into the same document as the GitHub metrics so the same
+// cards can be rendered.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// perPage is the page size of the lists walked, the largest Gitea allows by
+// default
+const perPage = 50
+
+// Collector collects metrics from Gitea or Forgejo
+type Collector struct {
+	API *Client
+	// HTTPClient is used to download the avatar embedded in the document
+	HTTPClient *http.Client
+	// Languages configures how the languages of the repositories are
+	// aggregated
+	Languages metrics.LanguageOptions
+	// Repositories selects the repositories the languages and the
+	// repository totals are aggregated over. The affiliations are not
+	// supported.
+	Repositories metrics.RepositoryFilter
+	// now returns the time the calendar ends at
+	now func() time.Time
+}
+
+// NewCollector creates a Collector for the API at the endpoint, ending in
+// /api/v1, authenticated with the given token
+func NewCollector(endpoint, token string) *Collector {
+	return &Collector{
+		API:        NewClient(endpoint, token),
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+		Languages:  metrics.LanguageOptions{Threshold: metrics.DefaultLanguageThreshold},
+		now:        time.Now,
+	}
+}
+
+// user is a Gitea user
+type user struct {
+	ID                int       `json:"id"`
+	Login             string    `json:"login"`
+	FullName          string    `json:"full_name"`
+	AvatarURL         string    `json:"avatar_url"`
+	Created           time.Time `json:"created"`
+	Followers         int       `json:"followers_count"`
+	Following         int       `json:"following_count"`
+	StarredReposCount int       `json:"starred_repos_count"`
+}
+
+// Collect collects the metrics of the user the token belongs to
+func (c *Collector) Collect(ctx context.Context) (*metrics.Metrics, error) {
+	var current user
+	if _, err := c.API.Get(ctx, "user", &current); err != nil {
+		return nil, fmt.Errorf("failed to get the Gitea user: %w", err)
+	}
+	return c.collect(ctx, current, true)
+}
+
+// CollectUser collects the metrics of the user with the login. Gitea only
+// searches the pull requests, reviews and issues of the user the token
+// belongs to, so those totals are 0 for other users.
+func (c *Collector) CollectUser(ctx context.Context, login string) (*metrics.Metrics, error) {
+	var u user
+	if _, err := c.API.Get(ctx, "users/"+url.PathEscape(login), &u); err != nil {
+		var statusErr *githubapi.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", metrics.ErrUserNotFound, login)
+		}
+		return nil, fmt.Errorf("failed to get Gitea user %s: %w", login, err)
+	}
+	own := false
+	if c.API.Token != "" {
+		var current user
+		if _, err := c.API.Get(ctx, "user", &current); err != nil {
+			return nil, fmt.Errorf("failed to get the Gitea user: %w", err)
+		}
+		own = current.ID == u.ID
+	}
+	return c.collect(ctx, u, own)
+}
+
+// collect collects the metrics of the user, own if the token belongs to them
+func (c *Collector) collect(ctx context.Context, u user, own bool) (*metrics.Metrics, error) {
+	now := c.now()
+	calendar, err := c.getContributionCalendar(ctx, u.Login, now)
+	if err != nil {
+		return nil, err
+	}
+	totals := &metrics.GitHubTotalsStats{TotalStarredRepos: u.StarredReposCount}
+	if own {
+		if err := c.countIssues(ctx, totals); err != nil {
+			return nil, err
+		}
+	}
+	totals.TotalCommits, err = c.getCommits(ctx, u.Login, now)
+	if err != nil {
+		return nil, err
+	}
+	list := "users/" + url.PathEscape(u.Login) + "/repos"
+	if own {
+		list = "user/repos"
+	}
+	repositories, err := c.getRepositories(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	totals.TotalRepositories = len(repositories)
+	for _, r := range repositories {
+		totals.TotalStargazers += r.StarsCount
+		totals.TotalForks += r.ForksCount
+	}
+	languages, err := c.getLanguageStats(ctx, repositories)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics.Metrics{
+		SchemaVersion: metrics.SchemaVersion,
+		GeneratedAt:   now.UTC(),
+		User: &metrics.GitHubUserInfo{
+			AvatarURL:     u.AvatarURL,
+			AvatarDataURI: metrics.AvatarDataURI(ctx, c.HTTPClient, u.AvatarURL),
+			Followers:     u.Followers,
+			Following:     u.Following,
+			JoinedGitHub:  u.Created,
+			Login:         u.Login,
+			Name:          u.FullName,
+			Type:          "User",
+		},
+		Totals:    totals,
+		Languages: languages,
+		Calendar:  calendar,
+	}, nil
+}
+
+// getContributionCalendar returns the calendar of the heatmap of the user,
+// which Gitea keeps for the last year
+func (c *Collector) getContributionCalendar(
+	ctx context.Context,
+	login string,
+	now time.Time,
+) (*metrics.ContributionCalendar, error) {
+	zap.L().Debug("Fetching Gitea contribution heatmap")
+
+	var heatmap []struct {
+		Timestamp     int64 `json:"timestamp"`
+		Contributions int   `json:"contributions"`
+	}
+	path := "users/" + url.PathEscape(login) + "/heatmap"
+	if _, err := c.API.Get(ctx, path, &heatmap); err != nil {
+		return nil, fmt.Errorf("failed to get the Gitea heatmap: %w", err)
+	}
+	// The heatmap counts the contributions of every quarter of an hour
+	counts := map[string]int{}
+	for _, entry := range heatmap {
+		counts[time.Unix(entry.Timestamp, 0).UTC().Format(time.DateOnly)] += entry.Contributions
+	}
+
+	calendar := metrics.NewContributionCalendar(counts, now)
+	zap.L().Debug("Gitea contribution calendar fetched",
+		zap.Int("total_contributions", calendar.TotalContributions))
+	return calendar, nil
+}
+
+// getCommits returns the commits the user pushed in the last year, from the
+// push activities of their feed
+func (c *Collector) getCommits(ctx context.Context, login string, now time.Time) (int, error) {
+	since := now.AddDate(-1, 0, 0)
+	commits := 0
+	for page := 1; ; page++ {
+		var activities []struct {
+			OpType  string    `json:"op_type"`
+			Content string    `json:"content"`
+			Created time.Time `json:"created"`
+		}
+		path := fmt.Sprintf(
+			"users/%s/activities/feeds?only-performed-by=true&limit=%d&page=%d",
+			url.PathEscape(login),
+			perPage,
+			page,
+		)
+		if _, err := c.API.Get(ctx, path, &activities); err != nil {
+			return 0, fmt.Errorf("failed to get Gitea activities: %w", err)
+		}
+		// Activities are listed newest first
+		for _, activity := range activities {
+			if activity.Created.Before(since) {
+				return commits, nil
+			}
+			if activity.OpType != "commit_repo" {
+				continue
+			}
+			var push struct {
+				Len int `json:"Len"`
+			}
+			if err := json.Unmarshal([]byte(activity.Content), &push); err != nil {
+				zap.L().Debug("Skipping push activity without commits", zap.Error(err))
+				continue
+			}
+			commits += push.Len
+		}
+		if len(activities) < perPage {
+			return commits, nil
+		}
+	}
+}
+
+// countIssues counts the pull requests, reviews and issues of the user the
+// token belongs to
+func (c *Collector) countIssues(ctx context.Context, totals *metrics.GitHubTotalsStats) error {
+	counts := []struct {
+		name  string
+		query string
+		total *int
+	}{
+		{"pull requests", "type=pulls&created=true", &totals.TotalPullRequests},
+		{"reviews", "type=pulls&reviewed=true", &totals.TotalPullRequestReviews},
+		{"issues", "type=issues&created=true", &totals.TotalIssues},
+	}
+	for _, count := range counts {
+		total, err := c.API.Count(ctx, "repos/issues/search?state=all&"+count.query)
+		if err != nil {
+			return fmt.Errorf("failed to count Gitea %s: %w", count.name, err)
+		}
+		*count.total = total
+	}
+	return nil
+}
+
+// repository is a Gitea repository
+type repository struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Owner    struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Fork       bool      `json:"fork"`
+	Archived   bool      `json:"archived"`
+	Private    bool      `json:"private"`
+	Internal   bool      `json:"internal"`
+	Topics     []string  `json:"topics"`
+	Empty      bool      `json:"empty"`
+	StarsCount int       `json:"stars_count"`
+	ForksCount int       `json:"forks_count"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// filtered returns the fields of the repository the repository filter
+// matches on
+func (r repository) filtered() metrics.Repository {
+	return metrics.Repository{
+		Owner:    r.Owner.Login,
+		Name:     r.Name,
+		Fork:     r.Fork,
+		Archived: r.Archived,
+		Private:  r.Private || r.Internal,
+		Topics:   r.Topics,
+	}
+}
+
+// getLanguageStats aggregates the languages of the repositories, leaving out
+// the empty ones
+func (c *Collector) getLanguageStats(
+	ctx context.Context,
+	repositories []repository,
+) ([]metrics.LanguageStat, error) {
+	zap.L().Debug("Fetching Gitea language statistics")
+
+	totals := metrics.NewLanguageTotals(c.Languages)
+	for _, r := range repositories {
+		if r.Empty {
+			continue
+		}
+		var languages map[string]int64
+		path := "repos/" + url.PathEscape(r.Owner.Login) + "/" + url.PathEscape(r.Name) +
+			"/languages"
+		if _, err := c.API.Get(ctx, path, &languages); err != nil {
+			return nil, fmt.Errorf("failed to get the languages of %s: %w", r.FullName, err)
+		}
+		for language, size := range languages {
+			totals.AddRepository(
+				r.FullName,
+				language,
+				metrics.LanguageColour(language),
+				size,
+				r.UpdatedAt,
+			)
+		}
+	}
+
+	languages := totals.Languages()
+	zap.L().Debug("Gitea language statistics fetched",
+		zap.Int("repositories", len(repositories)),
+		zap.Int("total_languages", len(languages)))
+	return languages, nil
+}
+
+// getRepositories returns every repository of the list at the path that the
+// repository filter selects
+func (c *Collector) getRepositories(ctx context.Context, path string) ([]repository, error) {
+	repositories := []repository{}
+	for page := 1; ; page++ {
+		var items []repository
+		pagePath := fmt.Sprintf("%s?limit=%d&page=%d", path, perPage, page)
+		if _, err := c.API.Get(ctx, pagePath, &items); err != nil {
+			return nil, fmt.Errorf("failed to get Gitea repositories: %w", err)
+		}
+		for _, r := range items {
+			if c.Repositories.Matches(r.filtered()) {
+				repositories = append(repositories, r)
+			}
+		}
+		if len(items) < perPage {
+			return repositories, nil
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+// testServer returns a stand-in for the REST API of a Gitea instance whose
+// token belongs to octocat
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	respond := func(pattern, body string, headers ...string) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "token token" {
+				t.Errorf("expected the token to be sent to %s", r.URL)
+			}
+			for i := 0; i+1 < len(headers); i += 2 {
+				w.Header().Set(headers[i], headers[i+1])
+			}
+			_, _ = w.Write([]byte(body))
+		})
+	}
+	octocat := `{"id": 7, "login": "octocat", "full_name": "Octo Cat",
+		"created": "2020-03-01T10:00:00Z", "followers_count": 5, "following_count": 2,
+		"starred_repos_count": 4}`
+	respond("GET /api/v1/user", octocat)
+	respond("GET /api/v1/users/octocat", octocat)
+	respond("GET /api/v1/users/hubot", `{"id": 8, "login": "hubot", "starred_repos_count": 1}`)
+	mux.HandleFunc("GET /api/v1/users/nobody", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message": "user does not exist"}`, http.StatusNotFound)
+	})
+	for _, login := range []string{"octocat", "hubot"} {
+		respond("GET /api/v1/users/"+login+"/heatmap", fmt.Sprintf(
+			`[{"timestamp": %d, "contributions": 2}, {"timestamp": %d, "contributions": 1},
+			{"timestamp": %d, "contributions": 1}]`,
+			time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC).Unix(),
+			time.Date(2026, 1, 6, 9, 15, 0, 0, time.UTC).Unix(),
+			time.Date(2026, 1, 2, 18, 30, 0, 0, time.UTC).Unix(),
+		))
+		respond("GET /api/v1/users/"+login+"/activities/feeds", `[
+			{"op_type": "commit_repo", "content": "{\"Len\": 3}", "created": "2026-01-06T09:00:00Z"},
+			{"op_type": "create_issue", "content": "1|Bug", "created": "2026-01-06T09:15:00Z"},
+			{"op_type": "commit_repo", "content": "{\"Len\": 2}", "created": "2026-01-02T18:30:00Z"},
+			{"op_type": "commit_repo", "content": "{\"Len\": 9}", "created": "2024-12-01T10:00:00Z"}
+		]`)
+	}
+	mux.HandleFunc("GET /api/v1/repos/issues/search", func(w http.ResponseWriter, r *http.Request) {
+		totals := map[string]string{"pulls": "12", "issues": "3"}
+		total := totals[r.URL.Query().Get("type")]
+		if r.URL.Query().Get("reviewed") == "true" {
+			total = "6"
+		}
+		w.Header().Set("X-Total-Count", total)
+		_, _ = w.Write([]byte(`[{}]`))
+	})
+	respond("GET /api/v1/user/repos", `[
+		{"name": "tool", "full_name": "octocat/tool", "owner": {"login": "octocat"},
+			"stars_count": 4, "forks_count": 2, "updated_at": "2025-12-01T10:00:00Z"},
+		{"name": "linux", "full_name": "octocat/linux", "owner": {"login": "octocat"}, "fork": true,
+			"stars_count": 100, "forks_count": 50},
+		{"name": "app", "full_name": "team/app", "owner": {"login": "team"}, "private": true,
+			"updated_at": "2025-06-01T10:00:00Z"},
+		{"name": "deps", "full_name": "team/deps", "owner": {"login": "team"},
+			"topics": ["vendored"]},
+		{"name": "new", "full_name": "octocat/new", "owner": {"login": "octocat"}, "empty": true,
+			"stars_count": 1}
+	]`)
+	respond(
+		"GET /api/v1/users/hubot/repos",
+		`[{"name": "bot", "full_name": "hubot/bot", "owner": {"login": "hubot"}, "stars_count": 2}]`,
+	)
+	respond("GET /api/v1/repos/octocat/tool/languages", `{"Go": 3000, "Shell": 1000}`)
+	respond("GET /api/v1/repos/octocat/linux/languages", `{"C": 100000}`)
+	respond("GET /api/v1/repos/team/app/languages", `{"Python": 2000}`)
+	respond("GET /api/v1/repos/team/deps/languages", `{"JavaScript": 9000}`)
+	respond("GET /api/v1/repos/hubot/bot/languages", `{"Ruby": 500}`)
+	return httptest.NewServer(mux)
+}
+
+func testCollector(server *httptest.Server) *Collector {
+	collector := NewCollector(server.URL+"/api/v1", "token")
+	collector.Repositories = metrics.RepositoryFilter{
+		ExcludeForks:  true,
+		ExcludeTopics: []string{"Vendored"},
+	}
+	collector.now = func() time.Time { return time.Date(2026, 1, 7, 12, 0, 0, 0, time.UTC) }
+	return collector
+}
+
+func TestCollectMapsGiteaOntoMetrics(t *testing.T) {
+	server := testServer(t)
+	defer server.Close()
+
+	document, err := testCollector(server).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("expected metrics, got %v", err)
+	}
+
+	if err := document.Validate(); err != nil {
+		t.Fatalf("expected a valid document, got %v", err)
+	}
+	if document.User.Login != "octocat" || document.User.Name != "Octo Cat" ||
+		document.User.Followers != 5 || document.User.JoinedGitHub.Year() != 2020 {
+		t.Fatalf("expected the Gitea user, got %+v", document.User)
+	}
+	want := metrics.GitHubTotalsStats{
+		TotalCommits:            5,
+		TotalPullRequests:       12,
+		TotalPullRequestReviews: 6,
+		TotalIssues:             3,
+		TotalStarredRepos:       4,
+		// The fork and the excluded repository are left out, the empty one
+		// is counted
+		TotalRepositories: 3,
+		TotalStargazers:   5,
+		TotalForks:        2,
+	}
+	if !reflect.DeepEqual(*document.Totals, want) {
+		t.Fatalf("expected totals %+v, got %+v", want, *document.Totals)
+	}
+	if document.Calendar.TotalContributions != 4 {
+		t.Fatalf("expected 4 contributions, got %d", document.Calendar.TotalContributions)
+	}
+	last := document.Calendar.Weeks[len(document.Calendar.Weeks)-1].ContributionDays
+	if last[2].Date != "2026-01-06" || last[2].ContributionCount != 3 ||
+		last[2].Color != theme.GitHubContribHigh {
+		t.Fatalf("expected the heatmap of a day to be summed, got %+v", last[2])
+	}
+	// The fork, the excluded and the empty repository are left out
+	if len(document.Languages) != 3 || document.Languages[0].Name != "Go" ||
+		document.Languages[0].TotalBytes != 3000 || document.Languages[0].Color != "#00ADD8" ||
+		document.Languages[1].Name != "Python" {
+		t.Fatalf("expected the languages of the repositories, got %+v", document.Languages)
+	}
+}
+
+func TestCollectUserLeavesOutTheIssuesOfOtherUsers(t *testing.T) {
+	server := testServer(t)
+	defer server.Close()
+
+	document, err := testCollector(server).CollectUser(context.Background(), "hubot")
+	if err != nil {
+		t.Fatalf("expected metrics, got %v", err)
+	}
+
+	want := metrics.GitHubTotalsStats{
+		TotalCommits:      5,
+		TotalStarredRepos: 1,
+		TotalRepositories: 1,
+		TotalStargazers:   2,
+	}
+	if !reflect.DeepEqual(*document.Totals, want) {
+		t.Fatalf("expected totals %+v, got %+v", want, *document.Totals)
+	}
+	if len(document.Languages) != 1 || document.Languages[0].Name != "Ruby" {
+		t.Fatalf("expected the public repositories of the user, got %+v", document.Languages)
+	}
+}
+
+func TestCollectUserReportsUnknownUsers(t *testing.T) {
+	server := testServer(t)
+	defer server.Close()
+
+	_, err := testCollector(server).CollectUser(context.Background(), "nobody")
+
+	if !errors.Is(err, metrics.ErrUserNotFound) {
+		t.Fatalf("expected the user not to be found, got %v", err)
+	}
+}
+
+func TestCollectLeavesOutPrivateRepositories(t *testing.T) {
+	server := testServer(t)
+	defer server.Close()
+	collector := testCollector(server)
+	collector.Repositories.ExcludePrivate = true
+	collector.Repositories.ExcludeNames = []string{"octocat/*"}
+
+	document, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("expected metrics, got %v", err)
+	}
+
+	if len(document.Languages) != 0 {
+		t.Fatalf("expected every repository to be left out, got %+v", document.Languages)
+	}
+}
@@ -1,12 +1,14 @@
-package main
+// Package githubapi provides minimal clients for the GitHub GraphQL and REST
+// APIs. Its REST client also serves the JSON REST APIs of GitLab and Gitea.
+package githubapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"time"
 
 	"go.uber.org/zap"
@@ -59,6 +61,7 @@ func NewGitHubGraphQLClient(token string) *GitHubGraphQLClient {
 
 // Query executes a GraphQL query against the GitHub API
 func (c *GitHubGraphQLClient) Query(
+	ctx context.Context,
 	query string,
 	variables map[string]interface{},
 	result interface{},
@@ -73,22 +76,27 @@ func (c *GitHubGraphQLClient) Query(
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.Endpoint, bytes.NewBuffer(reqBytes))
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		c.Endpoint,
+		bytes.NewBuffer(reqBytes),
+	)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Authorization", bearerPrefix+c.Token)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		zap.L().Fatal("Failed to query GitHub GraphQL API", zap.Error(err))
+		return fmt.Errorf("failed to query GitHub GraphQL API: %w", err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
-			zap.L().Fatal("Failed to close response body", zap.Error(cerr))
+			zap.L().Warn("Failed to close response body", zap.Error(cerr))
 		}
 	}()
 
@@ -119,10 +127,3 @@ func (c *GitHubGraphQLClient) Query(
 
 	return nil
 }
-
-// QueryGitHubQLAPI is a convenience function for making GitHub GraphQL queries
-func QueryGitHubQLAPI(query string, variables map[string]interface{}, result interface{}) error {
-	token := os.Getenv("INPUT_GITHUB_TOKEN")
-	client := NewGitHubGraphQLClient(token)
-	return client.Query(query, variables, result)
-}
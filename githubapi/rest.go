@@ -0,0 +1,130 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultGitHubRESTEndpoint is the default GitHub REST API endpoint
+	DefaultGitHubRESTEndpoint = "https://api.github.com"
+
+	bearerPrefix = "Bearer "
+)
+
+// GitHubRESTClient provides a client for making GET requests to the GitHub
+// REST API
+type GitHubRESTClient struct {
+	RESTClient
+}
+
+// NewGitHubRESTClient creates a new GitHub REST client
+func NewGitHubRESTClient(token string) *GitHubRESTClient {
+	client := &GitHubRESTClient{
+		RESTClient: *NewRESTClient("GitHub", DefaultGitHubRESTEndpoint, token),
+	}
+	client.Accept = "application/vnd.github+json"
+	return client
+}
+
+// Get requests the path relative to the endpoint and decodes the JSON
+// response into result
+func (c *GitHubRESTClient) Get(ctx context.Context, path string, result interface{}) error {
+	_, err := c.RESTClient.Get(ctx, path, result)
+	return err
+}
+
+// RESTClient provides a client for making GET requests to a JSON REST API.
+// The clients of GitHub, GitLab and Gitea share it, and differ in how they
+// send the token.
+type RESTClient struct {
+	// API names the API in errors, e.g. GitLab
+	API      string
+	Token    string
+	Endpoint string
+	Client   *http.Client
+	// Accept is the media type requested, application/json if empty
+	Accept string
+	// TokenHeader returns the header the token is sent in, the Authorization
+	// header with a bearer token if nil
+	TokenHeader func(token string) (name, value string)
+}
+
+// StatusError is returned by RESTClient.Get when the API responds with a
+// status other than 200 OK
+type StatusError struct {
+	// API names the API, e.g. GitHub
+	API        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s API returned non-200 status code %d: %s", e.API, e.StatusCode, e.Body)
+}
+
+// NewRESTClient creates a new client of the REST API named api at the
+// endpoint
+func NewRESTClient(api, endpoint, token string) *RESTClient {
+	return &RESTClient{
+		API:      api,
+		Token:    token,
+		Endpoint: endpoint,
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Get requests the path relative to the endpoint, decodes the JSON response
+// into result and returns the response headers, which hold the pagination
+func (c *RESTClient) Get(
+	ctx context.Context,
+	path string,
+	result interface{},
+) (http.Header, error) {
+	url := strings.TrimSuffix(c.Endpoint, "/") + "/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.Token != "" {
+		name, value := "Authorization", bearerPrefix+c.Token
+		if c.TokenHeader != nil {
+			name, value = c.TokenHeader(c.Token)
+		}
+		req.Header.Set(name, value)
+	}
+	accept := c.Accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s REST API: %w", c.API, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			zap.L().Warn("Failed to close response body", zap.Error(cerr))
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{API: c.API, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,51 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+)
+
+// DefaultEndpoint is the REST API endpoint of gitlab.com
+const DefaultEndpoint = "https://gitlab.com/api/v4"
+
+// Client provides a client for making GET requests to the GitLab REST API.
+// The Endpoint of self-hosted instances ends in /api/v4.
+type Client struct {
+	githubapi.RESTClient
+}
+
+// NewClient creates a new GitLab REST client for gitlab.com
+func NewClient(token string) *Client {
+	client := &Client{RESTClient: *githubapi.NewRESTClient("GitLab", DefaultEndpoint, token)}
+	client.TokenHeader = func(token string) (string, string) {
+		return "PRIVATE-TOKEN", token
+	}
+	return client
+}
+
+// maxCount is the largest count GitLab reports in the X-Total header
+const maxCount = 10000
+
+// Count returns the number of items of the list at the path from the X-Total
+// header, requesting a single item
+func (c *Client) Count(ctx context.Context, path string) (int, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	var items []json.RawMessage
+	header, err := c.Get(ctx, path+separator+"per_page=1", &items)
+	if err != nil {
+		return 0, err
+	}
+	total, err := strconv.Atoi(header.Get("X-Total"))
+	if err != nil && len(items) > 0 {
+		// GitLab leaves the header out of lists of more than 10,000 items
+		return maxCount, nil
+	}
+	return total, nil
+}
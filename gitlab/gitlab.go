@@ -0,0 +1,318 @@
+// Package gitlab collects coding metrics from the GitLab REST API, of
+// gitlab.com or a self-hosted instance, into the same document as the GitHub
+// metrics so the same cards can be rendered.
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// perPage is the page size of the lists walked
+const perPage = 100
+
+// Collector collects metrics from GitLab
+type Collector struct {
+	API *Client
+	// HTTPClient is used to download the avatar embedded in the document
+	HTTPClient *http.Client
+	// Languages configures how the languages of the projects are aggregated
+	Languages metrics.LanguageOptions
+	// Repositories selects the projects the languages and the repository
+	// totals are aggregated over.
+	// The owner of a project is its namespace, e.g. group/subgroup. The
+	// affiliations are not supported.
+	Repositories metrics.RepositoryFilter
+	// now returns the time the calendar ends at
+	now func() time.Time
+}
+
+// NewCollector creates a Collector for gitlab.com authenticated with the
+// given token. Set API.Endpoint for a self-hosted instance.
+func NewCollector(token string) *Collector {
+	return &Collector{
+		API:        NewClient(token),
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+		Languages:  metrics.LanguageOptions{Threshold: metrics.DefaultLanguageThreshold},
+		now:        time.Now,
+	}
+}
+
+// user is a GitLab user
+type user struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	AvatarURL string    `json:"avatar_url"`
+	CreatedAt time.Time `json:"created_at"`
+	Followers int       `json:"followers"`
+	Following int       `json:"following"`
+}
+
+// Collect collects the metrics of the user the token belongs to
+func (c *Collector) Collect(ctx context.Context) (*metrics.Metrics, error) {
+	var current user
+	if _, err := c.API.Get(ctx, "user", &current); err != nil {
+		return nil, fmt.Errorf("failed to get the GitLab user: %w", err)
+	}
+	return c.collect(ctx, current)
+}
+
+// CollectUser collects the metrics of the user with the username. Private
+// contributions are only included if the token belongs to that user.
+func (c *Collector) CollectUser(ctx context.Context, login string) (*metrics.Metrics, error) {
+	var users []user
+	if _, err := c.API.Get(ctx, "users?username="+url.QueryEscape(login), &users); err != nil {
+		return nil, fmt.Errorf("failed to get GitLab user %s: %w", login, err)
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("%w: %s", metrics.ErrUserNotFound, login)
+	}
+	return c.collect(ctx, users[0])
+}
+
+// collect collects the metrics of the user
+func (c *Collector) collect(ctx context.Context, u user) (*metrics.Metrics, error) {
+	now := c.now()
+	calendar, commits, err := c.getContributionCalendar(ctx, u.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := c.getProjects(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := c.getTotals(ctx, u.ID, projects)
+	if err != nil {
+		return nil, err
+	}
+	totals.TotalCommits = commits
+	languages, err := c.getLanguageStats(ctx, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics.Metrics{
+		SchemaVersion: metrics.SchemaVersion,
+		GeneratedAt:   now.UTC(),
+		User: &metrics.GitHubUserInfo{
+			AvatarURL:     u.AvatarURL,
+			AvatarDataURI: metrics.AvatarDataURI(ctx, c.HTTPClient, u.AvatarURL),
+			Followers:     u.Followers,
+			Following:     u.Following,
+			JoinedGitHub:  u.CreatedAt,
+			Login:         u.Username,
+			Name:          u.Name,
+			Type:          "User",
+		},
+		Totals:    totals,
+		Languages: languages,
+		Calendar:  calendar,
+	}, nil
+}
+
+// getContributionCalendar returns the calendar of the contribution events of
+// the user in the last year, and the commits they pushed in that time
+func (c *Collector) getContributionCalendar(
+	ctx context.Context,
+	userID int,
+	now time.Time,
+) (*metrics.ContributionCalendar, int, error) {
+	zap.L().Debug("Fetching GitLab contribution events")
+
+	// Events are listed after the given day, so from a year ago
+	after := now.AddDate(-1, 0, -1).Format(time.DateOnly)
+	counts := map[string]int{}
+	commits := 0
+	for page := "1"; page != ""; {
+		var events []struct {
+			CreatedAt time.Time `json:"created_at"`
+			PushData  *struct {
+				CommitCount int `json:"commit_count"`
+			} `json:"push_data"`
+		}
+		path := fmt.Sprintf(
+			"users/%d/events?after=%s&per_page=%d&page=%s",
+			userID,
+			after,
+			perPage,
+			page,
+		)
+		header, err := c.API.Get(ctx, path, &events)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get GitLab events: %w", err)
+		}
+		for _, event := range events {
+			counts[event.CreatedAt.UTC().Format(time.DateOnly)]++
+			if event.PushData != nil {
+				commits += event.PushData.CommitCount
+			}
+		}
+		page = header.Get("X-Next-Page")
+	}
+
+	calendar := metrics.NewContributionCalendar(counts, now)
+	zap.L().Debug("GitLab contribution calendar fetched",
+		zap.Int("total_contributions", calendar.TotalContributions),
+		zap.Int("commits", commits))
+	return calendar, commits, nil
+}
+
+// getTotals returns the merge requests, issues, reviews and starred projects
+// of the user, as the totals of the pull requests, issues, pull request
+// reviews and starred repositories, and the stars and forks of the projects
+func (c *Collector) getTotals(
+	ctx context.Context,
+	userID int,
+	projects []project,
+) (*metrics.GitHubTotalsStats, error) {
+	totals := &metrics.GitHubTotalsStats{TotalRepositories: len(projects)}
+	for _, p := range projects {
+		totals.TotalStargazers += p.StarCount
+		totals.TotalForks += p.ForksCount
+	}
+	counts := []struct {
+		path  string
+		total *int
+	}{
+		{
+			fmt.Sprintf("merge_requests?author_id=%d&scope=all&state=all", userID),
+			&totals.TotalPullRequests,
+		},
+		{
+			fmt.Sprintf("merge_requests?reviewer_id=%d&scope=all&state=all", userID),
+			&totals.TotalPullRequestReviews,
+		},
+		{fmt.Sprintf("issues?author_id=%d&scope=all&state=all", userID), &totals.TotalIssues},
+		{fmt.Sprintf("users/%d/starred_projects", userID), &totals.TotalStarredRepos},
+	}
+	for _, count := range counts {
+		total, err := c.API.Count(ctx, count.path)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to count GitLab %s: %w",
+				strings.Split(count.path, "?")[0],
+				err,
+			)
+		}
+		*count.total = total
+	}
+	return totals, nil
+}
+
+// project is a GitLab project
+type project struct {
+	ID                int       `json:"id"`
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+	Archived          bool      `json:"archived"`
+	Visibility        string    `json:"visibility"`
+	Topics            []string  `json:"topics"`
+	StarCount         int       `json:"star_count"`
+	ForksCount        int       `json:"forks_count"`
+	ForkedFromProject *struct{} `json:"forked_from_project"`
+	Statistics        *struct {
+		RepositorySize int64 `json:"repository_size"`
+	} `json:"statistics"`
+}
+
+// repository returns the fields of the project the repository filter
+// matches on
+func (p project) repository() metrics.Repository {
+	namespace, _ := strings.CutSuffix(p.PathWithNamespace, "/"+p.Path)
+	return metrics.Repository{
+		Owner:    namespace,
+		Name:     p.Path,
+		Fork:     p.ForkedFromProject != nil,
+		Archived: p.Archived,
+		Private:  p.Visibility != "public",
+		Topics:   p.Topics,
+	}
+}
+
+// unknownProjectSize is the size a project counts as when GitLab does not
+// report the size of its repository, which it only does to its members
+const unknownProjectSize = 100 * 1024
+
+// getLanguageStats aggregates the languages of the projects. GitLab reports
+// the languages of a project as percentages, so the size of each language is
+// its share of the repository size.
+func (c *Collector) getLanguageStats(
+	ctx context.Context,
+	projects []project,
+) ([]metrics.LanguageStat, error) {
+	zap.L().Debug("Fetching GitLab language statistics")
+
+	totals := metrics.NewLanguageTotals(c.Languages)
+	for _, p := range projects {
+		var languages map[string]float64
+		path := fmt.Sprintf("projects/%d/languages", p.ID)
+		if _, err := c.API.Get(ctx, path, &languages); err != nil {
+			return nil, fmt.Errorf(
+				"failed to get the languages of %s: %w",
+				p.PathWithNamespace,
+				err,
+			)
+		}
+		size := int64(unknownProjectSize)
+		if p.Statistics != nil && p.Statistics.RepositorySize > 0 {
+			size = p.Statistics.RepositorySize
+		}
+		for name, percentage := range languages {
+			totals.AddRepository(
+				p.PathWithNamespace,
+				name,
+				metrics.LanguageColour(name),
+				int64(percentage/100*float64(size)),
+				p.LastActivityAt,
+			)
+		}
+	}
+
+	languages := totals.Languages()
+	zap.L().Debug("GitLab language statistics fetched",
+		zap.Int("projects", len(projects)),
+		zap.Int("total_languages", len(languages)))
+	return languages, nil
+}
+
+// getProjects returns the projects the user owns and contributed to that the
+// repository filter selects
+func (c *Collector) getProjects(ctx context.Context, userID int) ([]project, error) {
+	projects := []project{}
+	seen := map[int]bool{}
+	for _, list := range []string{"projects", "contributed_projects"} {
+		for page := "1"; page != ""; {
+			var items []project
+			path := fmt.Sprintf(
+				"users/%d/%s?statistics=true&per_page=%d&page=%s",
+				userID,
+				list,
+				perPage,
+				page,
+			)
+			header, err := c.API.Get(ctx, path, &items)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitLab %s: %w", list, err)
+			}
+			for _, p := range items {
+				if seen[p.ID] || !c.Repositories.Matches(p.repository()) {
+					continue
+				}
+				seen[p.ID] = true
+				projects = append(projects, p)
+			}
+			page = header.Get("X-Next-Page")
+		}
+	}
+	return projects, nil
+}
@@ -0,0 +1,170 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// testServer returns a stand-in for the GitLab REST API of user 7
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	respond := func(pattern, body string, headers ...string) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("PRIVATE-TOKEN") != "token" {
+				t.Errorf("expected the token to be sent to %s", r.URL)
+			}
+			for i := 0; i+1 < len(headers); i += 2 {
+				w.Header().Set(headers[i], headers[i+1])
+			}
+			_, _ = w.Write([]byte(body))
+		})
+	}
+	respond("GET /api/v4/user", `{"id": 7, "username": "octocat", "name": "Octo Cat",
+		"created_at": "2020-03-01T10:00:00Z", "followers": 5, "following": 2}`)
+	respond("GET /api/v4/users", `[]`)
+	mux.HandleFunc("GET /api/v4/users/7/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("after") != "2025-01-06" {
+			t.Errorf("expected the events of the last year, got %s", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			_, _ = w.Write([]byte(`[
+				{"created_at": "2026-01-06T09:00:00Z", "push_data": {"commit_count": 3}},
+				{"created_at": "2026-01-06T11:00:00Z", "action_name": "opened"}
+			]`))
+			return
+		}
+		_, _ = w.Write(
+			[]byte(`[{"created_at": "2026-01-02T09:00:00Z", "push_data": {"commit_count": 2}}]`),
+		)
+	})
+	mux.HandleFunc("GET /api/v4/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("author_id") == "7":
+			w.Header().Set("X-Total", "12")
+		case r.URL.Query().Get("reviewer_id") == "7":
+			w.Header().Set("X-Total", "6")
+		default:
+			t.Errorf("expected the merge requests of the user, got %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`[{}]`))
+	})
+	respond("GET /api/v4/issues", `[{}]`, "X-Total", "3")
+	respond("GET /api/v4/users/7/starred_projects", `[]`, "X-Total", "0")
+	respond("GET /api/v4/users/7/projects", `[
+		{"id": 1, "path": "tool", "path_with_namespace": "octocat/tool", "visibility": "public",
+			"star_count": 4, "forks_count": 2, "statistics": {"repository_size": 4000}},
+		{"id": 2, "path": "linux", "path_with_namespace": "octocat/linux", "visibility": "public",
+			"star_count": 100, "forks_count": 50, "forked_from_project": {"id": 9}},
+		{"id": 4, "path": "deps", "path_with_namespace": "octocat/deps", "visibility": "public",
+			"topics": ["Vendored"]}
+	]`)
+	respond("GET /api/v4/users/7/contributed_projects", `[
+		{"id": 1, "path": "tool", "path_with_namespace": "octocat/tool", "visibility": "public",
+			"star_count": 4, "forks_count": 2},
+		{"id": 3, "path": "app", "path_with_namespace": "team/web/app", "visibility": "internal",
+			"star_count": 1},
+		{"id": 5, "path": "site", "path_with_namespace": "team/web/site", "visibility": "public"}
+	]`)
+	respond("GET /api/v4/projects/1/languages", `{"Go": 75, "Shell": 25}`)
+	respond("GET /api/v4/projects/2/languages", `{"C": 100}`)
+	respond("GET /api/v4/projects/3/languages", `{"Python": 1}`)
+	respond("GET /api/v4/projects/4/languages", `{"JavaScript": 100}`)
+	respond("GET /api/v4/projects/5/languages", `{"HTML": 100}`)
+	return httptest.NewServer(mux)
+}
+
+func testCollector(server *httptest.Server) *Collector {
+	collector := NewCollector("token")
+	collector.API.Endpoint = server.URL + "/api/v4"
+	collector.Repositories = metrics.RepositoryFilter{
+		ExcludeForks:  true,
+		ExcludeNames:  []string{"team/web/site"},
+		ExcludeTopics: []string{"vendored"},
+	}
+	collector.now = func() time.Time { return time.Date(2026, 1, 7, 12, 0, 0, 0, time.UTC) }
+	return collector
+}
+
+func TestCollectMapsGitLabOntoMetrics(t *testing.T) {
+	server := testServer(t)
+	defer server.Close()
+
+	document, err := testCollector(server).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("expected metrics, got %v", err)
+	}
+
+	if err := document.Validate(); err != nil {
+		t.Fatalf("expected a valid document, got %v", err)
+	}
+	if document.User.Login != "octocat" || document.User.Followers != 5 ||
+		document.User.JoinedGitHub.Year() != 2020 {
+		t.Fatalf("expected the GitLab user, got %+v", document.User)
+	}
+	want := metrics.GitHubTotalsStats{
+		TotalCommits:            5,
+		TotalPullRequests:       12,
+		TotalPullRequestReviews: 6,
+		TotalIssues:             3,
+		// The fork and the excluded projects are left out and the shared
+		// project counted once
+		TotalRepositories: 2,
+		TotalStargazers:   5,
+		TotalForks:        2,
+	}
+	if !reflect.DeepEqual(*document.Totals, want) {
+		t.Fatalf("expected totals %+v, got %+v", want, *document.Totals)
+	}
+	if document.Calendar.TotalContributions != 3 {
+		t.Fatalf("expected 3 contributions, got %d", document.Calendar.TotalContributions)
+	}
+	last := document.Calendar.Weeks[len(document.Calendar.Weeks)-1].ContributionDays
+	if last[2].Date != "2026-01-06" || last[2].ContributionCount != 2 {
+		t.Fatalf("expected the events of a day to be counted, got %+v", last)
+	}
+	// The fork and the excluded projects are left out and the shared project
+	// counted once
+	if len(document.Languages) != 3 || document.Languages[0].Name != "Go" ||
+		document.Languages[0].TotalBytes != 3000 || document.Languages[0].Color != "#00ADD8" ||
+		document.Languages[1].Name != "Python" || document.Languages[1].TotalBytes != 1024 {
+		t.Fatalf("expected the languages of the projects, got %+v", document.Languages)
+	}
+}
+
+func TestCollectUserReportsUnknownUsers(t *testing.T) {
+	server := testServer(t)
+	defer server.Close()
+
+	_, err := testCollector(server).CollectUser(context.Background(), "nobody")
+
+	if !errors.Is(err, metrics.ErrUserNotFound) {
+		t.Fatalf("expected the user not to be found, got %v", err)
+	}
+}
+
+func TestCollectLeavesOutPrivateProjects(t *testing.T) {
+	server := testServer(t)
+	defer server.Close()
+	collector := testCollector(server)
+	collector.Repositories.ExcludePrivate = true
+	collector.Repositories.ExcludeOwners = []string{"octocat"}
+
+	document, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("expected metrics, got %v", err)
+	}
+
+	// Internal projects are not public, and the owner is the namespace
+	if len(document.Languages) != 0 {
+		t.Fatalf("expected every project to be left out, got %+v", document.Languages)
+	}
+}
@@ -3,15 +3,20 @@ module github.com/JackPlowman/coding-metrics
 go 1.25.3
 
 require (
+	github.com/ProtonMail/go-crypto v1.5.2
 	github.com/google/go-github/v61 v61.0.0
 	github.com/twpayne/go-svg v1.0.0
 	go.uber.org/zap v1.27.0
+	go.yaml.in/yaml/v3 v3.0.5
 	golang.org/x/oauth2 v0.33.0
 )
 
 require (
+	github.com/cloudflare/circl v1.6.3 // indirect
 	github.com/google/go-cmp v0.7.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
 	github.com/stretchr/testify v1.11.1 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
 )
@@ -0,0 +1,274 @@
+// Package localgit collects coding metrics from the history of local git
+// clones, so cards can be built offline and for code that is not hosted on
+// GitHub.
+package localgit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// Separators of the records and fields of the git log format
+const (
+	recordSeparator = "\x1e"
+	fieldSeparator  = "\x1f"
+)
+
+// logFormat prints the hash, author email, author name and author date of
+// each commit, followed by the numstat of its files
+const logFormat = "--format=" + recordSeparator + "%H" + fieldSeparator + "%aE" +
+	fieldSeparator + "%aN" + fieldSeparator + "%aI"
+
+// unavailableStatLines are the stat lines of the card git history has no data
+// for
+var unavailableStatLines = []string{
+	"pull_request_reviews",
+	"pull_requests",
+	"issues",
+	"organizations",
+	"following",
+	"starred_repos",
+	"watching",
+	"sponsors",
+	"stargazers",
+	"forkers",
+	"watchers",
+}
+
+// Collector collects metrics from the commits of the user to the current
+// branch of local git repositories, by parsing the output of git log. Only
+// commits, repositories, the contribution calendar and the languages are
+// collected, the other stat lines are marked unavailable.
+type Collector struct {
+	// Repositories are the paths of the git clones
+	Repositories []string
+	// Authors are the emails of the commits of the user, matched
+	// case-insensitively. If empty, the user.email configured in each
+	// repository is used.
+	Authors []string
+	// Login is the login of the user, the local part of the author email of
+	// the latest commit if empty
+	Login string
+	// Languages configures how the languages of the lines the user added are
+	// aggregated
+	Languages metrics.LanguageOptions
+	// Git is the git executable
+	Git string
+	// now returns the time the calendar ends at
+	now func() time.Time
+}
+
+// NewCollector creates a Collector reading the repositories at the paths
+func NewCollector(repositories ...string) *Collector {
+	return &Collector{
+		Repositories: repositories,
+		Languages:    metrics.LanguageOptions{Threshold: metrics.DefaultLanguageThreshold},
+		Git:          "git",
+		now:          time.Now,
+	}
+}
+
+// commit is a commit read from git log
+type commit struct {
+	Hash  string
+	Email string
+	Name  string
+	Date  time.Time
+	Files []file
+}
+
+// file is a file changed by a commit
+type file struct {
+	Path      string
+	Additions int64
+}
+
+// Collect collects the metrics of the user from the repositories
+func (c *Collector) Collect(ctx context.Context) (*metrics.Metrics, error) {
+	if len(c.Repositories) == 0 {
+		return nil, errors.New("no local git repositories configured")
+	}
+
+	languages := metrics.NewLanguageTotals(c.Languages)
+	counts := map[string]int{}
+	seen := map[string]bool{}
+	var first, latest *commit
+	for _, repository := range c.Repositories {
+		authors, err := c.authors(ctx, repository)
+		if err != nil {
+			return nil, err
+		}
+		commits, err := c.log(ctx, repository)
+		if err != nil {
+			return nil, err
+		}
+		for i := range commits {
+			commit := &commits[i]
+			// Clones of the same repository share commits
+			if seen[commit.Hash] || !authors[strings.ToLower(commit.Email)] {
+				continue
+			}
+			seen[commit.Hash] = true
+			counts[commit.Date.Format(time.DateOnly)]++
+			for _, file := range commit.Files {
+				languages.AddFile(file.Path, file.Additions)
+			}
+			if first == nil || commit.Date.Before(first.Date) {
+				first = commit
+			}
+			if latest == nil || commit.Date.After(latest.Date) {
+				latest = commit
+			}
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("no commits of the authors found in the local git repositories")
+	}
+
+	login := c.Login
+	if login == "" {
+		login, _, _ = strings.Cut(latest.Email, "@")
+	}
+	calendar := metrics.NewContributionCalendar(counts, c.now())
+	zap.L().Debug("Local git metrics collected",
+		zap.Int("repositories", len(c.Repositories)),
+		zap.Int("commits", len(seen)),
+		zap.Int("total_contributions", calendar.TotalContributions))
+	return &metrics.Metrics{
+		SchemaVersion: metrics.SchemaVersion,
+		GeneratedAt:   c.now().UTC(),
+		User: &metrics.GitHubUserInfo{
+			Login:        login,
+			Name:         latest.Name,
+			Type:         "User",
+			JoinedGitHub: first.Date,
+		},
+		Totals: &metrics.GitHubTotalsStats{
+			TotalCommits:      len(seen),
+			TotalRepositories: len(c.Repositories),
+			Unavailable:       unavailableStatLines,
+		},
+		Languages: languages.Languages(),
+		Calendar:  calendar,
+	}, nil
+}
+
+// CollectUser collects the metrics of the user with the login. The
+// repositories only hold the commits of their user, so other logins are not
+// found.
+func (c *Collector) CollectUser(ctx context.Context, login string) (*metrics.Metrics, error) {
+	document, err := c.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(document.User.Login, login) {
+		return nil, fmt.Errorf("%w: %s", metrics.ErrUserNotFound, login)
+	}
+	return document, nil
+}
+
+// authors returns the lower case emails of the commits of the user in the
+// repository
+func (c *Collector) authors(ctx context.Context, repository string) (map[string]bool, error) {
+	emails := c.Authors
+	if len(emails) == 0 {
+		output, err := c.git(ctx, repository, "config", "user.email")
+		if err != nil || strings.TrimSpace(string(output)) == "" {
+			return nil, fmt.Errorf(
+				"no author emails configured and no user.email set in %s",
+				repository,
+			)
+		}
+		emails = []string{strings.TrimSpace(string(output))}
+	}
+	authors := map[string]bool{}
+	for _, email := range emails {
+		authors[strings.ToLower(email)] = true
+	}
+	return authors, nil
+}
+
+// log returns the commits of the current branch of the repository, leaving
+// out merge commits
+func (c *Collector) log(ctx context.Context, repository string) ([]commit, error) {
+	output, err := c.git(
+		ctx,
+		repository,
+		"log",
+		"--no-merges",
+		"--no-renames",
+		"--numstat",
+		"-z",
+		logFormat,
+		"HEAD",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(string(output))
+}
+
+// git runs git in the repository and returns its output
+func (c *Collector) git(ctx context.Context, repository string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Git, append([]string{"-C", repository}, args...)...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to run git %s in %s: %w: %s",
+			args[0],
+			repository,
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+	return output, nil
+}
+
+// parseLog parses the output of git log with logFormat, --numstat and -z.
+// The header and the numstat of every file are terminated by NUL, so paths
+// are printed verbatim rather than quoted.
+func parseLog(output string) ([]commit, error) {
+	commits := []commit{}
+	for _, record := range strings.Split(output, recordSeparator) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		entries := strings.Split(record, "\x00")
+		fields := strings.Split(entries[0], fieldSeparator)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git log header %q", entries[0])
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date of commit %s: %w", fields[0], err)
+		}
+		commit := commit{Hash: fields[0], Email: fields[1], Name: fields[2], Date: date}
+		for _, entry := range entries[1:] {
+			// Entries are "added\tdeleted\tpath", with "-" for binary files.
+			// The first follows the newline ending the header.
+			parts := strings.SplitN(strings.TrimPrefix(entry, "\n"), "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			additions, err := strconv.ParseInt(parts[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			commit.Files = append(commit.Files, file{Path: parts[2], Additions: additions})
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
@@ -0,0 +1,151 @@
+package localgit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// testRepository creates a git repository with the commits, each adding the
+// files with their contents, by the author email on the date
+func testRepository(t *testing.T, commits ...testCommit) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	run := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	run(nil, "init", "-q")
+	run(nil, "config", "user.email", "octocat@example.com")
+	run(nil, "config", "user.name", "Octo Cat")
+	for _, commit := range commits {
+		for name, content := range commit.files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		run(nil, "add", "-A")
+		run([]string{
+			"GIT_AUTHOR_EMAIL=" + commit.email,
+			"GIT_AUTHOR_NAME=" + commit.name,
+			"GIT_AUTHOR_DATE=" + commit.date,
+			"GIT_COMMITTER_DATE=" + commit.date,
+		}, "commit", "-q", "-m", "change")
+	}
+	return dir
+}
+
+type testCommit struct {
+	email, name, date string
+	files             map[string]string
+}
+
+func TestCollectReadsCommitsOfTheAuthors(t *testing.T) {
+	repo := testRepository(t,
+		testCommit{
+			"octocat@example.com", "Octo", "2026-01-02T10:00:00+00:00",
+			map[string]string{"main.go": "package main\n\nfunc main() {}\n", "README.md": "# Hi\n"},
+		},
+		testCommit{
+			"someone@example.com", "Someone", "2026-01-03T10:00:00+00:00",
+			map[string]string{"lib.py": "x = 1\ny = 2\n"},
+		},
+		testCommit{
+			"OctoCat@Example.com", "Octo Cat", "2026-01-05T23:30:00-05:00",
+			map[string]string{"web/app.ts": "export {}\n", "web/ünï\tcode.ts": "export {}\n"},
+		},
+	)
+	collector := NewCollector(repo, repo)
+	collector.now = func() time.Time { return time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC) }
+
+	document, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("expected metrics, got %v", err)
+	}
+
+	if err := document.Validate(); err != nil {
+		t.Fatalf("expected a valid document, got %v", err)
+	}
+	if document.User.Name != "Octo Cat" || document.User.Login != "OctoCat" {
+		t.Fatalf("expected the author of the latest commit, got %+v", document.User)
+	}
+	if document.Totals.TotalCommits != 2 {
+		t.Fatalf("expected the 2 commits of the user once, got %d", document.Totals.TotalCommits)
+	}
+	if document.Totals.TotalRepositories != 2 || len(document.Totals.Unavailable) == 0 {
+		t.Fatalf("expected a repository per clone and no other totals, got %+v", document.Totals)
+	}
+	if document.Calendar.TotalContributions != 2 {
+		t.Fatalf("expected 2 contributions, got %d", document.Calendar.TotalContributions)
+	}
+	counts := map[string]int{}
+	for _, week := range document.Calendar.Weeks {
+		for _, day := range week.ContributionDays {
+			counts[day.Date] = day.ContributionCount
+		}
+	}
+	// Commits are counted on the day of their author's time zone
+	if counts["2026-01-02"] != 1 || counts["2026-01-05"] != 1 || counts["2026-01-06"] != 0 {
+		t.Fatalf("expected a contribution on each day of a commit, got %v", counts)
+	}
+	if len(document.Languages) != 2 || document.Languages[0].Name != "Go" ||
+		document.Languages[0].Additions != 3 || document.Languages[1].Name != "TypeScript" ||
+		document.Languages[1].Additions != 2 {
+		t.Fatalf("expected the languages of the user's lines, got %+v", document.Languages)
+	}
+}
+
+func TestCollectUserOnlyFindsTheLogin(t *testing.T) {
+	repo := testRepository(t, testCommit{
+		"octocat@example.com", "Octo Cat", "2026-01-02T10:00:00+00:00",
+		map[string]string{"main.go": "package main\n"},
+	})
+	collector := NewCollector(repo)
+	collector.Login = "octocat"
+
+	if _, err := collector.CollectUser(context.Background(), "OctoCat"); err != nil {
+		t.Fatalf("expected the metrics of the login, got %v", err)
+	}
+	if _, err := collector.CollectUser(context.Background(), "someone"); !errors.Is(
+		err,
+		metrics.ErrUserNotFound,
+	) {
+		t.Fatalf("expected another login not to be found, got %v", err)
+	}
+}
+
+func TestParseLog(t *testing.T) {
+	output := "\x1eabc\x1fa@example.com\x1fA\x1f2026-01-02T10:00:00+01:00\x00\n" +
+		"3\t1\tcmd/my tool/\"main\".go\x00-\t-\tlogo.png\x00" +
+		"\x1edef\x1fb@example.com\x1fB\x1f2026-01-01T10:00:00Z\x00"
+
+	commits, err := parseLog(output)
+	if err != nil {
+		t.Fatalf("expected the log to be parsed, got %v", err)
+	}
+
+	if len(commits) != 2 || commits[0].Hash != "abc" || len(commits[0].Files) != 1 ||
+		commits[0].Files[0] != (file{Path: `cmd/my tool/"main".go`, Additions: 3}) ||
+		len(commits[1].Files) != 0 {
+		t.Fatalf("expected two commits with their text files, got %+v", commits)
+	}
+	if _, err := parseLog("\x1eabc\x1fa@example.com\n"); err == nil {
+		t.Fatalf("expected an error for a malformed header")
+	}
+}
@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+)
+
+// commitsPerPage is the page size of the REST commit listings
+const commitsPerPage = 100
+
+// pushedRepository is a repository with the time it was last pushed to
+type pushedRepository struct {
+	repositoryInfo
+	PushedAt time.Time `json:"pushedAt"`
+}
+
+// authoredCommit is a commit of the user to a repository
+type authoredCommit struct {
+	Repository repositoryInfo
+	SHA        string
+	// Date is the date the commit was committed
+	Date time.Time
+}
+
+// getRepositories fetches the repositories the filter selects, the most
+// recently pushed to first
+func (c *Collector) getRepositories(
+	ctx context.Context,
+	userName string,
+) ([]pushedRepository, error) {
+	query := `
+	query($login: String!, $after: String, $affiliations: [RepositoryAffiliation]) {
+		user(login: $login) {
+			repositories(
+				first: 100,
+				after: $after,
+				ownerAffiliations: $affiliations,
+				orderBy: {field: PUSHED_AT, direction: DESC}
+			) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					...RepositoryFilterFields
+					pushedAt
+				}
+			}
+		}
+	}` + repositoryFilterFields
+
+	variables := map[string]interface{}{
+		"login":        userName,
+		"affiliations": c.Repositories.affiliations(),
+	}
+
+	repositories := []pushedRepository{}
+	hasNextPage := true
+	cursor := ""
+
+	for hasNextPage {
+		if cursor != "" {
+			variables["after"] = cursor
+		}
+
+		var result struct {
+			User struct {
+				Repositories struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []pushedRepository `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+
+		if err := c.GraphQL.Query(ctx, query, variables, &result); err != nil {
+			return nil, fmt.Errorf("failed to get repositories: %w", err)
+		}
+
+		for _, repo := range result.User.Repositories.Nodes {
+			if c.Repositories.matches(repo.repositoryInfo) {
+				repositories = append(repositories, repo)
+			}
+		}
+
+		hasNextPage = result.User.Repositories.PageInfo.HasNextPage
+		cursor = result.User.Repositories.PageInfo.EndCursor
+	}
+	return repositories, nil
+}
+
+// getAuthoredLanguageStats aggregates the lines the user added per language
+// in their most recent commits across the repositories the filter selects.
+// The GraphQL commit history has no changed files, so the commits are walked
+// with the REST API, one request per commit.
+func (c *Collector) getAuthoredLanguageStats(
+	ctx context.Context,
+	userName string,
+) ([]LanguageStat, error) {
+	limit := c.Languages.authoredCommits()
+	zap.L().Debug("Fetching authored language statistics", zap.Int("max_commits", limit))
+
+	repositories, err := c.getRepositories(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []authoredCommit{}
+	for _, repo := range repositories {
+		// A repository has no commits newer than its last push, and the
+		// repositories come most recently pushed to first
+		if len(commits) == limit && !commits[limit-1].Date.Before(repo.PushedAt) {
+			break
+		}
+		listed, err := c.getAuthoredCommits(ctx, repo.repositoryInfo, userName, limit)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, listed...)
+		sort.SliceStable(commits, func(i, j int) bool {
+			return commits[i].Date.After(commits[j].Date)
+		})
+		commits = commits[:min(len(commits), limit)]
+	}
+
+	aggregator := newLanguageAggregator(c.Languages, time.Now())
+	for _, commit := range commits {
+		if err := c.addCommitLanguages(ctx, commit.Repository, commit.SHA, aggregator); err != nil {
+			return nil, err
+		}
+	}
+
+	languages := aggregator.languages()
+	zap.L().Debug("Authored language statistics fetched",
+		zap.Int("total_languages", len(languages)),
+		zap.Int("commits", len(commits)))
+	return languages, nil
+}
+
+// getAuthoredCommits returns up to limit of the most recent commits of the
+// user to the default branch of the repository, leaving out merge commits
+func (c *Collector) getAuthoredCommits(
+	ctx context.Context,
+	repo repositoryInfo,
+	userName string,
+	limit int,
+) ([]authoredCommit, error) {
+	authored := []authoredCommit{}
+	for page := 1; len(authored) < limit; page++ {
+		path := fmt.Sprintf(
+			"repos/%s/%s/commits?author=%s&per_page=%d&page=%d",
+			url.PathEscape(repo.Owner.Login),
+			url.PathEscape(repo.Name),
+			url.QueryEscape(userName),
+			commitsPerPage,
+			page,
+		)
+		var commits []struct {
+			SHA    string `json:"sha"`
+			Commit struct {
+				Committer struct {
+					Date time.Time `json:"date"`
+				} `json:"committer"`
+			} `json:"commit"`
+			Parents []struct {
+				SHA string `json:"sha"`
+			} `json:"parents"`
+		}
+		if err := c.REST.Get(ctx, path, &commits); err != nil {
+			// Empty repositories have no commits to list
+			var statusErr *githubapi.StatusError
+			if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict {
+				return authored, nil
+			}
+			return nil, fmt.Errorf(
+				"failed to get the commits of %s/%s: %w",
+				repo.Owner.Login,
+				repo.Name,
+				err,
+			)
+		}
+		for _, commit := range commits {
+			if len(commit.Parents) <= 1 && len(authored) < limit {
+				authored = append(authored, authoredCommit{
+					Repository: repo,
+					SHA:        commit.SHA,
+					Date:       commit.Commit.Committer.Date,
+				})
+			}
+		}
+		if len(commits) < commitsPerPage {
+			break
+		}
+	}
+	return authored, nil
+}
+
+// addCommitLanguages adds the lines added by the commit to the aggregator by
+// the language of each changed file
+func (c *Collector) addCommitLanguages(
+	ctx context.Context,
+	repo repositoryInfo,
+	sha string,
+	aggregator *languageAggregator,
+) error {
+	path := fmt.Sprintf(
+		"repos/%s/%s/commits/%s",
+		url.PathEscape(repo.Owner.Login),
+		url.PathEscape(repo.Name),
+		url.PathEscape(sha),
+	)
+	var commit struct {
+		Files []struct {
+			Filename  string `json:"filename"`
+			Additions int64  `json:"additions"`
+		} `json:"files"`
+	}
+	if err := c.REST.Get(ctx, path, &commit); err != nil {
+		return fmt.Errorf(
+			"failed to get commit %s of %s/%s: %w",
+			sha,
+			repo.Owner.Login,
+			repo.Name,
+			err,
+		)
+	}
+	for _, file := range commit.Files {
+		if name, colour, ok := LanguageForPath(file.Filename); ok && file.Additions > 0 {
+			aggregator.addAdditions(name, colour, file.Additions)
+		}
+	}
+	return nil
+}
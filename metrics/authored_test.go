@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLanguageForPath(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{"main.go", "Go"},
+		{"web/src/App.TSX", "TSX"},
+		{"build/Dockerfile", "Dockerfile"},
+		{"Makefile", "Makefile"},
+		{"scripts/deploy.sh", "Shell"},
+		{"README.md", ""},
+		{"config.yml", ""},
+		{"vendor/github.com/pkg/errors/errors.go", ""},
+		{"web/node_modules/react/index.js", ""},
+		{"static/app.min.js", ""},
+	} {
+		name, _, ok := LanguageForPath(tc.path)
+		if name != tc.want || ok != (tc.want != "") {
+			t.Fatalf("expected %q to be %q, got %q (%v)", tc.path, tc.want, name, ok)
+		}
+	}
+}
+
+func TestGetAuthoredLanguageStatsWalksCommits(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"user": {"repositories": {
+			"pageInfo": {"hasNextPage": false, "endCursor": ""},
+			"nodes": [
+				{"name": "empty", "owner": {"login": "octocat"}, "pushedAt": "2026-01-05T10:00:00Z"},
+				{"name": "tool", "owner": {"login": "octocat"}, "pushedAt": "2026-01-04T10:00:00Z"},
+				{"name": "app", "owner": {"login": "octocat"}, "pushedAt": "2026-01-03T10:00:00Z"},
+				{"name": "old", "owner": {"login": "octocat"}, "pushedAt": "2025-01-01T10:00:00Z"},
+				{"name": "linux", "owner": {"login": "octocat"}, "isFork": true}
+			]
+		}}}}`))
+	})
+	commit := func(sha, date string, parents int) string {
+		return fmt.Sprintf(
+			`{"sha": %q, "commit": {"committer": {"date": %q}}, "parents": [%s]}`,
+			sha,
+			date,
+			strings.TrimSuffix(strings.Repeat(`{"sha": "p"},`, parents), ","),
+		)
+	}
+	listings := map[string]string{
+		"tool": "[" + strings.Join([]string{
+			commit("a1", "2026-01-04T10:00:00Z", 1),
+			commit("m1", "2026-01-03T12:00:00Z", 2),
+			commit("b2", "2026-01-01T10:00:00Z", 1),
+			commit("c3", "2025-12-01T10:00:00Z", 1),
+		}, ",") + "]",
+		"app": "[" + commit("d4", "2026-01-02T10:00:00Z", 1) + "]",
+	}
+	listed := map[string]bool{}
+	mux.HandleFunc(
+		"GET /repos/octocat/{repo}/commits",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("author") != "octocat" {
+				t.Errorf("expected the commits of the user, got %s", r.URL.RawQuery)
+			}
+			repo := r.PathValue("repo")
+			listed[repo] = true
+			if repo == "empty" {
+				http.Error(w, `{"message": "Git Repository is empty."}`, http.StatusConflict)
+				return
+			}
+			_, _ = w.Write([]byte(listings[repo]))
+		},
+	)
+	files := map[string]string{
+		"a1": `[{"filename": "main.go", "additions": 30}, {"filename": "go.sum", "additions": 500}]`,
+		"d4": `[{"filename": "ui/App.tsx", "additions": 10}, {"filename": "main_test.go", "additions": 0}]`,
+	}
+	requested := map[string]bool{}
+	mux.HandleFunc(
+		"GET /repos/octocat/{repo}/commits/{sha}",
+		func(w http.ResponseWriter, r *http.Request) {
+			sha := r.PathValue("sha")
+			requested[sha] = true
+			_, _ = fmt.Fprintf(w, `{"sha": %q, "files": %s}`, sha, files[sha])
+		},
+	)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	collector := NewCollector("token")
+	collector.GraphQL.Endpoint = server.URL + "/graphql"
+	collector.REST.Endpoint = server.URL
+	collector.Repositories = RepositoryFilter{ExcludeForks: true}
+	collector.Languages = LanguageOptions{
+		Authored:        true,
+		AuthoredCommits: 2,
+		Aliases:         map[string]string{"TSX": "TypeScript"},
+	}
+	languages, err := collector.getAuthoredLanguageStats(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("expected authored language statistics, got %v", err)
+	}
+
+	// The old repository was last pushed to before the two newest commits
+	if !listed["empty"] || !listed["app"] || listed["old"] || listed["linux"] {
+		t.Fatalf("expected the repositories pushed to since the newest commits, got %v", listed)
+	}
+	if len(requested) != 2 || !requested["a1"] || !requested["d4"] {
+		t.Fatalf("expected the two newest commits without merges to be walked, got %v", requested)
+	}
+	if len(languages) != 2 || languages[0].Name != "Go" || languages[0].Additions != 30 ||
+		languages[1].Name != "TypeScript" || languages[1].Percentage != 25 {
+		t.Fatalf("expected the additions per language, got %+v", languages)
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"math"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+// calendarWeeks is the number of full weeks before the current week in a
+// contribution calendar, a year like on GitHub
+const calendarWeeks = 52
+
+// contributionColours are the GitHub colours of the contribution levels 0-4,
+// which the renderer maps to the colours of the profile
+var contributionColours = []string{
+	theme.GitHubContribNone,
+	theme.GitHubContribLow,
+	theme.GitHubContribMediumLow,
+	theme.GitHubContribMediumHigh,
+	theme.GitHubContribHigh,
+}
+
+// NewContributionCalendar returns a contribution calendar like GitHub's for
+// data sources other than GitHub, from the number of contributions on each
+// date in YYYY-MM-DD form. It covers the weeks from Sunday a year before now
+// to the day of now. The levels of the days are the quarters of the busiest
+// day they fall into.
+func NewContributionCalendar(counts map[string]int, now time.Time) *ContributionCalendar {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, -calendarWeeks*7-int(today.Weekday()))
+
+	busiest := 0
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		busiest = max(busiest, counts[day.Format(time.DateOnly)])
+	}
+
+	calendar := &ContributionCalendar{Weeks: make([]ContributionWeek, 0, calendarWeeks+1)}
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Sunday {
+			calendar.Weeks = append(calendar.Weeks, ContributionWeek{
+				ContributionDays: make([]ContributionDay, 0, 7),
+			})
+		}
+		date := day.Format(time.DateOnly)
+		count := counts[date]
+		week := &calendar.Weeks[len(calendar.Weeks)-1]
+		week.ContributionDays = append(week.ContributionDays, ContributionDay{
+			Date:              date,
+			ContributionCount: count,
+			Color:             contributionColours[contributionLevel(count, busiest)],
+		})
+		calendar.TotalContributions += count
+	}
+	return calendar
+}
+
+// contributionLevel returns the level 0-4 of a day with count contributions
+// in a calendar whose busiest day has busiest contributions
+func contributionLevel(count, busiest int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	return min(int(math.Ceil(4*float64(count)/float64(busiest))), 4)
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+func TestNewContributionCalendarCoversAYearOfWeeks(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 1, 7, 15, 0, 0, 0, time.UTC)
+	counts := map[string]int{"2026-01-07": 8, "2026-01-05": 1, "2025-01-03": 5, "2024-12-01": 9}
+
+	calendar := NewContributionCalendar(counts, now)
+
+	if len(calendar.Weeks) != 53 {
+		t.Fatalf("expected 53 weeks, got %d", len(calendar.Weeks))
+	}
+	first := calendar.Weeks[0].ContributionDays[0]
+	if first.Date != "2025-01-05" {
+		t.Fatalf("expected the calendar to start on a Sunday a year ago, got %s", first.Date)
+	}
+	last := calendar.Weeks[52].ContributionDays
+	if len(last) != 4 || last[3].Date != "2026-01-07" {
+		t.Fatalf("expected the last week to end today, got %+v", last)
+	}
+	if calendar.TotalContributions != 9 {
+		t.Fatalf("expected the contributions of the year, got %d", calendar.TotalContributions)
+	}
+	if last[3].Color != theme.GitHubContribHigh || last[1].Color != theme.GitHubContribLow ||
+		last[0].Color != theme.GitHubContribNone {
+		t.Fatalf("expected levels relative to the busiest day, got %+v", last)
+	}
+}
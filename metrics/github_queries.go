@@ -0,0 +1,779 @@
+package metrics
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const maxAvatarBytes = 2 * 1024 * 1024
+
+// GitHubUserInfo is the user's profile information from the GitHub REST API
+type GitHubUserInfo struct {
+	AvatarURL     string    `json:"avatar_url"`
+	AvatarDataURI string    `json:"avatar_data_uri,omitempty"`
+	Followers     int       `json:"followers"`
+	Following     int       `json:"following"`
+	JoinedGitHub  time.Time `json:"created_at"`
+	Login         string    `json:"login"`
+	Name          string    `json:"name"`
+	PublicGists   int       `json:"public_gists"`
+	PublicRepos   int       `json:"public_repos"`
+	Type          string    `json:"type"`
+}
+
+// getGitHubUserInfo fetches the authenticated user's information from the
+// GitHub REST API
+func (c *Collector) getGitHubUserInfo(ctx context.Context) (*GitHubUserInfo, error) {
+	var user GitHubUserInfo
+	if err := c.REST.Get(ctx, "user", &user); err != nil {
+		return nil, fmt.Errorf("failed to get GitHub user info: %w", err)
+	}
+	return &user, nil
+}
+
+// getUser fetches the public information of the user with the given login
+// from the GitHub REST API
+func (c *Collector) getUser(ctx context.Context, login string) (*GitHubUserInfo, error) {
+	var user GitHubUserInfo
+	if err := c.REST.Get(ctx, "users/"+url.PathEscape(login), &user); err != nil {
+		return nil, fmt.Errorf("failed to get GitHub user %s: %w", login, err)
+	}
+	return &user, nil
+}
+
+// normalizeAvatarURL ensures the avatar URL renders when embedded in sanitized SVGs
+func normalizeAvatarURL(avatarURL string) string {
+	if avatarURL == "" {
+		return avatarURL
+	}
+
+	parsed, err := url.Parse(avatarURL)
+	if err != nil {
+		zap.L().
+			Warn("Failed to parse avatar URL", zap.String("avatar_url", avatarURL), zap.Error(err))
+		return avatarURL
+	}
+
+	query := parsed.Query()
+	if query.Get("s") == "" && query.Get("size") == "" {
+		query.Set("s", "80")
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// getAvatarDataURI returns a self-contained avatar data URI where possible.
+// SVGs embedded as images often cannot load external image subresources, so
+// using a data URI keeps the GitHub avatar visible in README/profile renders.
+func (c *Collector) getAvatarDataURI(ctx context.Context, avatarURL string) string {
+	return AvatarDataURI(ctx, c.HTTPClient, avatarURL)
+}
+
+// AvatarDataURI downloads the avatar at the URL with the client and returns
+// it as a data URI, or "" if it cannot be downloaded. Providers embed the
+// avatar in the document with it.
+func AvatarDataURI(ctx context.Context, client *http.Client, avatarURL string) string {
+	normalizedURL := normalizeAvatarURL(avatarURL)
+	if normalizedURL == "" {
+		return ""
+	}
+
+	return fetchAvatarDataURI(ctx, client, normalizedURL)
+}
+
+// AvatarHref returns the avatar data URI fetched with the metrics, falling
+// back to the avatar URL.
+func (u *GitHubUserInfo) AvatarHref() string {
+	if u.AvatarDataURI != "" {
+		return u.AvatarDataURI
+	}
+	return normalizeAvatarURL(u.AvatarURL)
+}
+
+func fetchAvatarDataURI(ctx context.Context, client *http.Client, avatarURL string) string {
+	req, err := http.NewRequestWithContext(ctx, "GET", avatarURL, nil)
+	if err != nil {
+		zap.L().
+			Warn("Failed to create avatar request", zap.String("avatar_url", avatarURL), zap.Error(err))
+		return ""
+	}
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("User-Agent", "coding-metrics")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		zap.L().Warn("Failed to fetch avatar", zap.String("avatar_url", avatarURL), zap.Error(err))
+		return ""
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			zap.L().Warn("Failed to close avatar response body", zap.Error(cerr))
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		zap.L().Warn("Avatar request returned non-200 status",
+			zap.String("avatar_url", avatarURL),
+			zap.Int("status", resp.StatusCode))
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAvatarBytes+1))
+	if err != nil {
+		zap.L().
+			Warn("Failed to read avatar response", zap.String("avatar_url", avatarURL), zap.Error(err))
+		return ""
+	}
+	if len(body) == 0 || len(body) > maxAvatarBytes {
+		zap.L().Warn("Avatar response was empty or too large",
+			zap.String("avatar_url", avatarURL),
+			zap.Int("bytes", len(body)))
+		return ""
+	}
+
+	contentType := cleanImageContentType(resp.Header.Get("Content-Type"), body)
+	if contentType == "" {
+		zap.L().Warn("Avatar response was not an image",
+			zap.String("avatar_url", avatarURL),
+			zap.String("content_type", resp.Header.Get("Content-Type")))
+		return ""
+	}
+
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(body)
+}
+
+func cleanImageContentType(contentType string, body []byte) string {
+	contentType = strings.TrimSpace(strings.Split(contentType, ";")[0])
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return ""
+	}
+	return contentType
+}
+
+// getUserId fetches the user ID for a given username
+func (c *Collector) getUserId(ctx context.Context, userName string) (string, error) {
+	zap.L().Debug("Fetching user ID", zap.String("username", userName))
+	userQuery := `
+	query($login: String!) {
+		user(login: $login) {
+			id
+		}
+	}`
+
+	var userResult struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+
+	userVariables := map[string]interface{}{
+		"login": userName,
+	}
+
+	if err := c.GraphQL.Query(ctx, userQuery, userVariables, &userResult); err != nil {
+		return "", fmt.Errorf("failed to query user ID: %w", err)
+	}
+
+	return userResult.User.ID, nil
+}
+
+// repositoryTotals are the totals of the repositories of the user
+type repositoryTotals struct {
+	Commits      int
+	Repositories int
+	Stargazers   int
+	Forks        int
+}
+
+// getRepositoryTotals fetches the total number of commits made by a user to
+// default branches across all repositories, along with the number of those
+// repositories and their stargazers and forks
+func (c *Collector) getRepositoryTotals(
+	ctx context.Context,
+	userName, userId string,
+) (*repositoryTotals, error) {
+	zap.L().
+		Debug("Fetching total commits")
+	// Now query repositories and commits using the user ID
+	query := `
+	query($login: String!, $userId: ID!, $after: String, $affiliations: [RepositoryAffiliation]) {
+		user(login: $login) {
+			repositories(first: 100, after: $after, ownerAffiliations: $affiliations) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					...RepositoryFilterFields
+					stargazerCount
+					forkCount
+					defaultBranchRef {
+						target {
+							... on Commit {
+								history(author: {id: $userId}) {
+									totalCount
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}` + repositoryFilterFields
+
+	variables := map[string]interface{}{
+		"login":        userName,
+		"userId":       userId,
+		"affiliations": c.Repositories.affiliations(),
+	}
+
+	totals := &repositoryTotals{}
+	hasNextPage := true
+	cursor := ""
+
+	for hasNextPage {
+		if cursor != "" {
+			variables["after"] = cursor
+		}
+
+		var result struct {
+			User struct {
+				Repositories struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						repositoryInfo
+						StargazerCount   int `json:"stargazerCount"`
+						ForkCount        int `json:"forkCount"`
+						DefaultBranchRef *struct {
+							Target struct {
+								History struct {
+									TotalCount int `json:"totalCount"`
+								} `json:"history"`
+							} `json:"target"`
+						} `json:"defaultBranchRef"`
+					} `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+
+		if err := c.GraphQL.Query(ctx, query, variables, &result); err != nil {
+			return nil, fmt.Errorf("failed to get commits total: %w", err)
+		}
+
+		for _, repo := range result.User.Repositories.Nodes {
+			if !c.Repositories.matches(repo.repositoryInfo) {
+				continue
+			}
+			totals.Repositories++
+			totals.Stargazers += repo.StargazerCount
+			totals.Forks += repo.ForkCount
+			if repo.DefaultBranchRef != nil {
+				totals.Commits += repo.DefaultBranchRef.Target.History.TotalCount
+			}
+		}
+
+		hasNextPage = result.User.Repositories.PageInfo.HasNextPage
+		cursor = result.User.Repositories.PageInfo.EndCursor
+	}
+
+	zap.L().
+		Debug("Total commits by user",
+			zap.Int("total_commits", totals.Commits),
+			zap.Int("total_repositories", totals.Repositories))
+	return totals, nil
+}
+
+// GitHubTotals holds the user's activity totals other than commits
+type GitHubTotals struct {
+	TotalPullRequests          int
+	TotalIssues                int
+	TotalPullRequestReviews    int
+	TotalStarredRepos          int
+	TotalSponsors              int
+	TotalMemberOfOrganizations int
+	TotalWatchers              int
+}
+
+func (c *Collector) getGitHubTotals(ctx context.Context, userName string) (*GitHubTotals, error) {
+	zap.L().
+		Debug("Fetching GitHub totals")
+	query := `
+	query($login: String!) {
+		user(login: $login) {
+			issues {
+				totalCount
+			}
+			pullRequests {
+				totalCount
+			}
+			contributionsCollection {
+				pullRequestReviewContributions {
+					totalCount
+				}
+			}
+			starredRepositories {
+				totalCount
+			}
+			sponsorshipsAsMaintainer {
+				totalCount
+			}
+			organizations {
+				totalCount
+			}
+			watching {
+				totalCount
+			}
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"login": userName,
+	}
+
+	var result struct {
+		User struct {
+			Issues struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"issues"`
+			PullRequests struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"pullRequests"`
+			ContributionsCollection struct {
+				PullRequestReviewContributions struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"pullRequestReviewContributions"`
+			} `json:"contributionsCollection"`
+			StarredRepositories struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"starredRepositories"`
+			SponsorshipsAsMaintainer struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"sponsorshipsAsMaintainer"`
+			Organizations struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"organizations"`
+			Watching struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"watching"`
+		} `json:"user"`
+	}
+
+	if err := c.GraphQL.Query(ctx, query, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get GitHub totals: %w", err)
+	}
+
+	response := &GitHubTotals{
+		TotalPullRequests:          result.User.PullRequests.TotalCount,
+		TotalIssues:                result.User.Issues.TotalCount,
+		TotalPullRequestReviews:    result.User.ContributionsCollection.PullRequestReviewContributions.TotalCount,
+		TotalStarredRepos:          result.User.StarredRepositories.TotalCount,
+		TotalSponsors:              result.User.SponsorshipsAsMaintainer.TotalCount,
+		TotalMemberOfOrganizations: result.User.Organizations.TotalCount,
+		TotalWatchers:              result.User.Watching.TotalCount,
+	}
+	zap.L().
+		Debug("GitHub totals fetched",
+			zap.Int("total_pull_requests", response.TotalPullRequests),
+			zap.Int("total_issues", response.TotalIssues),
+			zap.Int("total_pr_reviews", response.TotalPullRequestReviews),
+			zap.Int("total_starred_repos", response.TotalStarredRepos),
+			zap.Int("total_sponsors", response.TotalSponsors),
+			zap.Int("total_member_of_organizations", response.TotalMemberOfOrganizations),
+			zap.Int("total_watchers", response.TotalWatchers),
+		)
+	return response, nil
+}
+
+// GitHubTotalsStats holds the user's activity totals shown on the card
+type GitHubTotalsStats struct {
+	TotalCommits               int `json:"total_commits"`
+	TotalIssues                int `json:"total_issues"`
+	TotalPullRequests          int `json:"total_pull_requests"`
+	TotalPullRequestReviews    int `json:"total_pull_request_reviews"`
+	TotalStarredRepos          int `json:"total_starred_repos"`
+	TotalSponsors              int `json:"total_sponsors"`
+	TotalMemberOfOrganizations int `json:"total_member_of_organizations"`
+	TotalWatchers              int `json:"total_watchers"`
+	TotalRepositories          int `json:"total_repositories"`
+	TotalStargazers            int `json:"total_stargazers"`
+	TotalForks                 int `json:"total_forks"`
+	// Unavailable lists the stat lines the data source does not provide, e.g.
+	// "sponsors", which are hidden from the card rather than shown as 0
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+func (c *Collector) getGitHubTotalsStats(
+	ctx context.Context,
+	userName, userId string,
+) (*GitHubTotalsStats, error) {
+	totals, err := c.getGitHubTotals(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := c.getRepositoryTotals(ctx, userName, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubTotalsStats{
+		TotalCommits:               repositories.Commits,
+		TotalPullRequests:          totals.TotalPullRequests,
+		TotalIssues:                totals.TotalIssues,
+		TotalPullRequestReviews:    totals.TotalPullRequestReviews,
+		TotalStarredRepos:          totals.TotalStarredRepos,
+		TotalSponsors:              totals.TotalSponsors,
+		TotalMemberOfOrganizations: totals.TotalMemberOfOrganizations,
+		TotalWatchers:              totals.TotalWatchers,
+		TotalRepositories:          repositories.Repositories,
+		TotalStargazers:            repositories.Stargazers,
+		TotalForks:                 repositories.Forks,
+	}, nil
+}
+
+// LanguageStat represents statistics for a programming language
+type LanguageStat struct {
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+	TotalBytes int64  `json:"total_bytes"`
+	// Additions are the lines the user added in the language, set for the
+	// authored languages
+	Additions  int64   `json:"additions,omitempty"`
+	Percentage float64 `json:"percentage"`
+}
+
+// languageEdges is a page of the languages connection of a repository
+type languageEdges struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Edges []struct {
+		Size int64 `json:"size"`
+		Node struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"node"`
+	} `json:"edges"`
+}
+
+// addTo adds the languages of the page in the repository to the aggregator
+func (l languageEdges) addTo(aggregator *languageAggregator, repo languageRepository) {
+	for _, edge := range l.Edges {
+		aggregator.add(repo, edge.Node.Name, edge.Node.Color, edge.Size)
+	}
+}
+
+// getLanguageStats fetches and aggregates language statistics across all user repositories
+func (c *Collector) getLanguageStats(
+	ctx context.Context,
+	userName, userId string,
+) ([]LanguageStat, error) {
+	zap.L().Debug("Fetching language statistics", zap.String("weighting", c.Languages.Weighting))
+
+	query := `
+	query(
+		$login: String!,
+		$userId: ID,
+		$after: String,
+		$affiliations: [RepositoryAffiliation],
+		$commits: Boolean!
+	) {
+		user(login: $login) {
+			repositories(first: 100, after: $after, ownerAffiliations: $affiliations) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					...RepositoryFilterFields
+					pushedAt
+					primaryLanguage {
+						name
+					}
+					defaultBranchRef @include(if: $commits) {
+						target {
+							... on Commit {
+								history(author: {id: $userId}) {
+									totalCount
+								}
+							}
+						}
+					}
+					languages(first: 100, orderBy: {field: SIZE, direction: DESC}) {
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+						edges {
+							size
+							node {
+								name
+								color
+							}
+						}
+					}
+				}
+			}
+		}
+	}` + repositoryFilterFields
+
+	variables := map[string]interface{}{
+		"login":        userName,
+		"userId":       userId,
+		"affiliations": c.Repositories.affiliations(),
+		"commits":      c.Languages.Weighting == WeightingCommits,
+	}
+
+	// Aggregate language bytes across all repositories
+	aggregator := newLanguageAggregator(c.Languages, time.Now())
+
+	hasNextPage := true
+	cursor := ""
+
+	for hasNextPage {
+		if cursor != "" {
+			variables["after"] = cursor
+		}
+
+		var result struct {
+			User struct {
+				Repositories struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						repositoryInfo
+						PushedAt        time.Time `json:"pushedAt"`
+						PrimaryLanguage *struct {
+							Name string `json:"name"`
+						} `json:"primaryLanguage"`
+						DefaultBranchRef *struct {
+							Target struct {
+								History struct {
+									TotalCount int `json:"totalCount"`
+								} `json:"history"`
+							} `json:"target"`
+						} `json:"defaultBranchRef"`
+						Languages languageEdges `json:"languages"`
+					} `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+
+		if err := c.GraphQL.Query(ctx, query, variables, &result); err != nil {
+			return nil, fmt.Errorf("failed to get language statistics: %w", err)
+		}
+
+		for _, repo := range result.User.Repositories.Nodes {
+			if !c.Repositories.matches(repo.repositoryInfo) {
+				continue
+			}
+			weighted := languageRepository{
+				Name:     repo.Owner.Login + "/" + repo.Name,
+				PushedAt: repo.PushedAt,
+			}
+			if repo.PrimaryLanguage != nil {
+				weighted.PrimaryLanguage = repo.PrimaryLanguage.Name
+			}
+			if repo.DefaultBranchRef != nil {
+				weighted.Commits = repo.DefaultBranchRef.Target.History.TotalCount
+			}
+			repo.Languages.addTo(aggregator, weighted)
+			if repo.Languages.PageInfo.HasNextPage {
+				err := c.getRemainingRepositoryLanguages(
+					ctx,
+					repo.Owner.Login,
+					repo.Name,
+					repo.Languages.PageInfo.EndCursor,
+					aggregator,
+					weighted,
+				)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		hasNextPage = result.User.Repositories.PageInfo.HasNextPage
+		cursor = result.User.Repositories.PageInfo.EndCursor
+	}
+
+	languages := aggregator.languages()
+	if len(languages) == 0 {
+		zap.L().Debug("No language data found")
+		return languages, nil
+	}
+
+	zap.L().Debug("Language statistics fetched",
+		zap.Int("total_languages", len(languages)),
+		zap.Int64("total_bytes", aggregator.total))
+
+	return languages, nil
+}
+
+// getRemainingRepositoryLanguages fetches the pages of the languages of a
+// repository after the cursor, for repositories with more languages than fit
+// on the first page
+func (c *Collector) getRemainingRepositoryLanguages(
+	ctx context.Context,
+	owner, name, cursor string,
+	aggregator *languageAggregator,
+	repo languageRepository,
+) error {
+	query := `
+	query($owner: String!, $name: String!, $after: String) {
+		repository(owner: $owner, name: $name) {
+			languages(first: 100, after: $after, orderBy: {field: SIZE, direction: DESC}) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				edges {
+					size
+					node {
+						name
+						color
+					}
+				}
+			}
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  name,
+	}
+	for cursor != "" {
+		variables["after"] = cursor
+
+		var result struct {
+			Repository struct {
+				Languages languageEdges `json:"languages"`
+			} `json:"repository"`
+		}
+		if err := c.GraphQL.Query(ctx, query, variables, &result); err != nil {
+			return fmt.Errorf("failed to get the languages of %s/%s: %w", owner, name, err)
+		}
+
+		result.Repository.Languages.addTo(aggregator, repo)
+		cursor = ""
+		if result.Repository.Languages.PageInfo.HasNextPage {
+			cursor = result.Repository.Languages.PageInfo.EndCursor
+		}
+	}
+	return nil
+}
+
+// ContributionDay represents a single day's contribution data
+type ContributionDay struct {
+	Date              string `json:"date"`
+	ContributionCount int    `json:"contribution_count"`
+	Color             string `json:"color"`
+}
+
+// ContributionCalendar represents the contribution calendar data
+type ContributionCalendar struct {
+	TotalContributions int                `json:"total_contributions"`
+	Weeks              []ContributionWeek `json:"weeks"`
+}
+
+// ContributionWeek represents a week of contribution days
+type ContributionWeek struct {
+	ContributionDays []ContributionDay `json:"contribution_days"`
+}
+
+// getContributionCalendar fetches the user's contribution calendar from GitHub
+func (c *Collector) getContributionCalendar(
+	ctx context.Context,
+	userName string,
+) (*ContributionCalendar, error) {
+	zap.L().Debug("Fetching contribution calendar")
+
+	query := `
+	query($login: String!) {
+		user(login: $login) {
+			contributionsCollection {
+				contributionCalendar {
+					totalContributions
+					weeks {
+						contributionDays {
+							date
+							contributionCount
+							color
+						}
+					}
+				}
+			}
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"login": userName,
+	}
+
+	var result struct {
+		User struct {
+			ContributionsCollection struct {
+				ContributionCalendar struct {
+					TotalContributions int `json:"totalContributions"`
+					Weeks              []struct {
+						ContributionDays []struct {
+							Date              string `json:"date"`
+							ContributionCount int    `json:"contributionCount"`
+							Color             string `json:"color"`
+						} `json:"contributionDays"`
+					} `json:"weeks"`
+				} `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+
+	if err := c.GraphQL.Query(ctx, query, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get contribution calendar: %w", err)
+	}
+
+	// Convert the result to our data structure
+	calendar := &ContributionCalendar{
+		TotalContributions: result.User.ContributionsCollection.ContributionCalendar.TotalContributions,
+		Weeks:              make([]ContributionWeek, 0),
+	}
+
+	for _, week := range result.User.ContributionsCollection.ContributionCalendar.Weeks {
+		contributionWeek := ContributionWeek{
+			ContributionDays: make([]ContributionDay, 0),
+		}
+		for _, day := range week.ContributionDays {
+			contributionWeek.ContributionDays = append(
+				contributionWeek.ContributionDays,
+				ContributionDay{
+					Date:              day.Date,
+					ContributionCount: day.ContributionCount,
+					Color:             day.Color,
+				},
+			)
+		}
+		calendar.Weeks = append(calendar.Weeks, contributionWeek)
+	}
+
+	zap.L().Debug("Contribution calendar fetched",
+		zap.Int("total_contributions", calendar.TotalContributions),
+		zap.Int("total_weeks", len(calendar.Weeks)))
+
+	return calendar, nil
+}
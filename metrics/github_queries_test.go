@@ -1,6 +1,7 @@
-package main
+package metrics
 
 import (
+	"context"
 	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
@@ -38,7 +39,7 @@ func TestFetchAvatarDataURIEmbedsImage(t *testing.T) {
 	}))
 	t.Cleanup(server.Close)
 
-	got := fetchAvatarDataURI(server.Client(), server.URL)
+	got := fetchAvatarDataURI(context.Background(), server.Client(), server.URL)
 	want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(avatarBytes)
 
 	if got != want {
@@ -53,7 +54,7 @@ func TestFetchAvatarDataURIRejectsNonImage(t *testing.T) {
 	}))
 	t.Cleanup(server.Close)
 
-	if got := fetchAvatarDataURI(server.Client(), server.URL); got != "" {
+	if got := fetchAvatarDataURI(context.Background(), server.Client(), server.URL); got != "" {
 		t.Fatalf("expected non-image response to be rejected, got %q", got)
 	}
 }
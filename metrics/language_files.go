@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"path"
+	"strings"
+)
+
+// fileLanguage is a language detected from a file path, with its GitHub
+// colour
+type fileLanguage struct {
+	Name   string
+	Colour string
+}
+
+// Languages detected from file paths, named and coloured like GitHub does
+var (
+	langAssembly   = fileLanguage{"Assembly", "#6E4C13"}
+	langBatchfile  = fileLanguage{"Batchfile", "#C1F12E"}
+	langC          = fileLanguage{"C", "#555555"}
+	langCPP        = fileLanguage{"C++", "#f34b7d"}
+	langCSharp     = fileLanguage{"C#", "#178600"}
+	langCSS        = fileLanguage{"CSS", "#663399"}
+	langClojure    = fileLanguage{"Clojure", "#db5855"}
+	langCMake      = fileLanguage{"CMake", "#DA3434"}
+	langDart       = fileLanguage{"Dart", "#00B4AB"}
+	langDockerfile = fileLanguage{"Dockerfile", "#384d54"}
+	langElixir     = fileLanguage{"Elixir", "#6e4a7e"}
+	langEmacsLisp  = fileLanguage{"Emacs Lisp", "#c065db"}
+	langErlang     = fileLanguage{"Erlang", "#B83998"}
+	langFSharp     = fileLanguage{"F#", "#b845fc"}
+	langGo         = fileLanguage{"Go", "#00ADD8"}
+	langGraphQL    = fileLanguage{"GraphQL", "#e10098"}
+	langGroovy     = fileLanguage{"Groovy", "#4298b8"}
+	langHCL        = fileLanguage{"HCL", "#844FBA"}
+	langHTML       = fileLanguage{"HTML", "#e34c26"}
+	langHaskell    = fileLanguage{"Haskell", "#5e5086"}
+	langJava       = fileLanguage{"Java", "#b07219"}
+	langJavaScript = fileLanguage{"JavaScript", "#f1e05a"}
+	langJulia      = fileLanguage{"Julia", "#a270ba"}
+	langJupyter    = fileLanguage{"Jupyter Notebook", "#DA5B0B"}
+	langKotlin     = fileLanguage{"Kotlin", "#A97BFF"}
+	langLess       = fileLanguage{"Less", "#1d365d"}
+	langLua        = fileLanguage{"Lua", "#000080"}
+	langMakefile   = fileLanguage{"Makefile", "#427819"}
+	langNix        = fileLanguage{"Nix", "#7e7eff"}
+	langOCaml      = fileLanguage{"OCaml", "#ef7a08"}
+	langObjectiveC = fileLanguage{"Objective-C", "#438eff"}
+	langPHP        = fileLanguage{"PHP", "#4F5D95"}
+	langPerl       = fileLanguage{"Perl", "#0298c3"}
+	langPowerShell = fileLanguage{"PowerShell", "#012456"}
+	langPython     = fileLanguage{"Python", "#3572A5"}
+	langR          = fileLanguage{"R", "#198CE7"}
+	langRuby       = fileLanguage{"Ruby", "#701516"}
+	langRust       = fileLanguage{"Rust", "#dea584"}
+	langSCSS       = fileLanguage{"SCSS", "#c6538c"}
+	langSQL        = fileLanguage{"SQL", "#e38c00"}
+	langScala      = fileLanguage{"Scala", "#c22d40"}
+	langShell      = fileLanguage{"Shell", "#89e051"}
+	langSolidity   = fileLanguage{"Solidity", "#AA6746"}
+	langSvelte     = fileLanguage{"Svelte", "#ff3e00"}
+	langSwift      = fileLanguage{"Swift", "#F05138"}
+	langTSX        = fileLanguage{"TSX", "#3178c6"}
+	langTypeScript = fileLanguage{"TypeScript", "#3178c6"}
+	langVimScript  = fileLanguage{"Vim Script", "#199f4b"}
+	langVue        = fileLanguage{"Vue", "#41b883"}
+	langZig        = fileLanguage{"Zig", "#ec915c"}
+)
+
+// extensionLanguages maps lower case file extensions to languages. Like the
+// repository languages on GitHub, data and prose formats such as JSON, YAML
+// and Markdown are not languages.
+var extensionLanguages = map[string]fileLanguage{
+	".asm":        langAssembly,
+	".bash":       langShell,
+	".bat":        langBatchfile,
+	".c":          langC,
+	".cc":         langCPP,
+	".cjs":        langJavaScript,
+	".clj":        langClojure,
+	".cljc":       langClojure,
+	".cljs":       langClojure,
+	".cmake":      langCMake,
+	".cmd":        langBatchfile,
+	".cpp":        langCPP,
+	".cs":         langCSharp,
+	".css":        langCSS,
+	".cts":        langTypeScript,
+	".cxx":        langCPP,
+	".dart":       langDart,
+	".dockerfile": langDockerfile,
+	".el":         langEmacsLisp,
+	".erl":        langErlang,
+	".ex":         langElixir,
+	".exs":        langElixir,
+	".fs":         langFSharp,
+	".go":         langGo,
+	".gql":        langGraphQL,
+	".gradle":     langGroovy,
+	".graphql":    langGraphQL,
+	".groovy":     langGroovy,
+	".h":          langC,
+	".hcl":        langHCL,
+	".hh":         langCPP,
+	".hpp":        langCPP,
+	".hs":         langHaskell,
+	".htm":        langHTML,
+	".html":       langHTML,
+	".hxx":        langCPP,
+	".ipynb":      langJupyter,
+	".java":       langJava,
+	".jl":         langJulia,
+	".js":         langJavaScript,
+	".jsx":        langJavaScript,
+	".kt":         langKotlin,
+	".kts":        langKotlin,
+	".less":       langLess,
+	".lua":        langLua,
+	".m":          langObjectiveC,
+	".mjs":        langJavaScript,
+	".mk":         langMakefile,
+	".ml":         langOCaml,
+	".mli":        langOCaml,
+	".mts":        langTypeScript,
+	".nix":        langNix,
+	".php":        langPHP,
+	".pl":         langPerl,
+	".pm":         langPerl,
+	".ps1":        langPowerShell,
+	".psm1":       langPowerShell,
+	".py":         langPython,
+	".pyi":        langPython,
+	".r":          langR,
+	".rb":         langRuby,
+	".rs":         langRust,
+	".s":          langAssembly,
+	".scala":      langScala,
+	".scss":       langSCSS,
+	".sh":         langShell,
+	".sol":        langSolidity,
+	".sql":        langSQL,
+	".svelte":     langSvelte,
+	".swift":      langSwift,
+	".tf":         langHCL,
+	".ts":         langTypeScript,
+	".tsx":        langTSX,
+	".vim":        langVimScript,
+	".vue":        langVue,
+	".zig":        langZig,
+	".zsh":        langShell,
+}
+
+// filenameLanguages maps file names without a telling extension to
+// languages
+var filenameLanguages = map[string]fileLanguage{
+	"cmakelists.txt": langCMake,
+	"dockerfile":     langDockerfile,
+	"gnumakefile":    langMakefile,
+	"makefile":       langMakefile,
+	"rakefile":       langRuby,
+	"gemfile":        langRuby,
+	".vimrc":         langVimScript,
+	".bashrc":        langShell,
+	".zshrc":         langShell,
+}
+
+// LanguageColour returns the GitHub colour of the language with the name,
+// matched case-insensitively, or "" for languages detected from no file path
+func LanguageColour(name string) string {
+	for _, languages := range []map[string]fileLanguage{extensionLanguages, filenameLanguages} {
+		for _, language := range languages {
+			if strings.EqualFold(language.Name, name) {
+				return language.Colour
+			}
+		}
+	}
+	return ""
+}
+
+// vendoredDirectories hold code of others, which is not counted as authored
+var vendoredDirectories = []string{"vendor", "node_modules", "third_party", "bower_components"}
+
+// LanguageForPath returns the name and colour of the language of the file at
+// the slash separated path, or false for files of no language and vendored
+// or minified files
+func LanguageForPath(filePath string) (name, colour string, ok bool) {
+	for _, directory := range strings.Split(path.Dir(filePath), "/") {
+		for _, vendored := range vendoredDirectories {
+			if directory == vendored {
+				return "", "", false
+			}
+		}
+	}
+	base := strings.ToLower(path.Base(filePath))
+	if strings.Contains(base, ".min.") {
+		return "", "", false
+	}
+	language, found := filenameLanguages[base]
+	if !found {
+		language, found = extensionLanguages[path.Ext(base)]
+	}
+	return language.Name, language.Colour, found
+}
@@ -0,0 +1,330 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultLanguageThreshold is the minimum percentage of the total size a
+// language needs to be listed
+const DefaultLanguageThreshold = 1.0
+
+// Weightings of the languages, deciding what the percentages of the languages
+// are shares of
+const (
+	// WeightingBytes weights languages by their size in bytes
+	WeightingBytes = "bytes"
+	// WeightingRepositories weights languages by the number of repositories
+	// using them
+	WeightingRepositories = "repos"
+	// WeightingRecency weights languages by their size, halving the weight of
+	// a repository every RecencyHalfLife since it was last pushed to
+	WeightingRecency = "recency"
+	// WeightingCommits weights the primary language of each repository by the
+	// number of commits of the user to it
+	WeightingCommits = "commits"
+)
+
+// Weightings lists the supported language weightings
+var Weightings = []string{
+	WeightingBytes,
+	WeightingRepositories,
+	WeightingRecency,
+	WeightingCommits,
+}
+
+// RecencyHalfLife is the time it takes the weight of a repository to halve
+// with the recency weighting
+const RecencyHalfLife = 180 * 24 * time.Hour
+
+// LanguageOptions configures how the languages of the repositories are
+// aggregated
+type LanguageOptions struct {
+	// Exclude lists languages that are left out, matched case-insensitively
+	Exclude []string
+	// Aliases merges languages into another, keyed by the merged language,
+	// e.g. "TSX" to "TypeScript". Names are matched case-insensitively.
+	Aliases map[string]string
+	// Threshold is the minimum percentage of the total size a language needs
+	// to be listed, 0 to list every language
+	Threshold float64
+	// Top is the maximum number of languages listed, 0 for all
+	Top int
+	// Weighting is one of Weightings, WeightingBytes if empty
+	Weighting string
+	// Authored aggregates the lines the user added in their commits instead
+	// of the repository languages, ignoring the weighting
+	Authored bool
+	// AuthoredCommits is the maximum number of commits walked for the
+	// authored languages, DefaultAuthoredCommits if 0
+	AuthoredCommits int
+}
+
+// DefaultAuthoredCommits is the number of the most recent commits of the user
+// walked for the authored languages. Every commit takes a request.
+const DefaultAuthoredCommits = 300
+
+// authoredCommits returns the maximum number of commits walked for the
+// authored languages
+func (o LanguageOptions) authoredCommits() int {
+	if o.AuthoredCommits > 0 {
+		return o.AuthoredCommits
+	}
+	return DefaultAuthoredCommits
+}
+
+// Validate checks the weighting of the options
+func (o LanguageOptions) Validate() error {
+	if o.Weighting != "" && !slices.Contains(Weightings, o.Weighting) {
+		return fmt.Errorf(
+			"unknown language weighting %q, expected one of %s",
+			o.Weighting,
+			strings.Join(Weightings, ", "),
+		)
+	}
+	return nil
+}
+
+// languageRepository holds the fields of a repository the weightings need
+type languageRepository struct {
+	// Name identifies the repository, so the repositories weighting counts
+	// it once per language
+	Name            string
+	PushedAt        time.Time
+	PrimaryLanguage string
+	// Commits is the number of commits of the user to the repository, only
+	// fetched for the commits weighting
+	Commits int
+}
+
+// weight returns the weight of size bytes of the language in the repository
+func (o LanguageOptions) weight(
+	repo languageRepository,
+	language string,
+	size int64,
+	now time.Time,
+) float64 {
+	switch o.Weighting {
+	case WeightingRepositories:
+		return 1
+	case WeightingRecency:
+		age := max(now.Sub(repo.PushedAt), 0)
+		return float64(size) * math.Pow(0.5, float64(age)/float64(RecencyHalfLife))
+	case WeightingCommits:
+		if !strings.EqualFold(language, repo.PrimaryLanguage) {
+			return 0
+		}
+		return float64(repo.Commits)
+	default:
+		return float64(size)
+	}
+}
+
+// ParseLanguageAliases parses comma or newline separated "Alias=Language"
+// pairs
+func ParseLanguageAliases(value string) (map[string]string, error) {
+	aliases := map[string]string{}
+	for _, pair := range ParseList(value) {
+		alias, language, found := strings.Cut(pair, "=")
+		alias, language = strings.TrimSpace(alias), strings.TrimSpace(language)
+		if !found || alias == "" || language == "" {
+			return nil, fmt.Errorf("invalid language alias %q, expected \"Alias=Language\"", pair)
+		}
+		aliases[alias] = language
+	}
+	return aliases, nil
+}
+
+// languageAggregator sums the size and weight of each language across
+// repositories, applying the exclusions and aliases of the options
+type languageAggregator struct {
+	options LanguageOptions
+	// now is the time the recency weighting measures the age of repositories
+	// from
+	now      time.Time
+	excluded map[string]bool
+	aliases  map[string]string
+	// stats are keyed by the lower case name of the language
+	stats map[string]*LanguageStat
+	// coloured records languages whose colour is their own rather than the
+	// colour of a language merged into them
+	coloured map[string]bool
+	// weights are the weights of the languages, keyed like stats
+	weights map[string]float64
+	// counted records the repositories counted for each language by the
+	// repositories weighting, keyed by the name of the repository and the
+	// key of the stat
+	counted     map[string]bool
+	total       int64
+	totalWeight float64
+}
+
+func newLanguageAggregator(options LanguageOptions, now time.Time) *languageAggregator {
+	a := &languageAggregator{
+		options:  options,
+		now:      now,
+		weights:  map[string]float64{},
+		counted:  map[string]bool{},
+		excluded: map[string]bool{},
+		aliases:  map[string]string{},
+		stats:    map[string]*LanguageStat{},
+		coloured: map[string]bool{},
+	}
+	for _, name := range options.Exclude {
+		a.excluded[strings.ToLower(name)] = true
+	}
+	for alias, name := range options.Aliases {
+		a.aliases[strings.ToLower(alias)] = name
+	}
+	return a
+}
+
+// add adds size bytes of the language in the repository
+func (a *languageAggregator) add(repo languageRepository, name, colour string, size int64) {
+	// The weight is computed before aliasing, as the primary language of the
+	// repository is the language GitHub reports
+	weight := a.options.weight(repo, name, size, a.now)
+	if stat := a.stat(name, colour); stat != nil {
+		stat.TotalBytes += size
+		a.total += size
+		// Languages aliased together are one language of the repository
+		if a.options.Weighting == WeightingRepositories {
+			key := repo.Name + "\x00" + strings.ToLower(stat.Name)
+			if a.counted[key] {
+				weight = 0
+			}
+			a.counted[key] = true
+		}
+		a.addWeight(stat.Name, weight)
+	}
+}
+
+// addAdditions adds lines the user added in the language, weighted by their
+// number
+func (a *languageAggregator) addAdditions(name, colour string, additions int64) {
+	if stat := a.stat(name, colour); stat != nil {
+		stat.Additions += additions
+		a.addWeight(stat.Name, float64(additions))
+	}
+}
+
+func (a *languageAggregator) addWeight(name string, weight float64) {
+	a.weights[strings.ToLower(name)] += weight
+	a.totalWeight += weight
+}
+
+// stat returns the stat the language is aggregated into after aliasing, or
+// nil if it is excluded
+func (a *languageAggregator) stat(name, colour string) *LanguageStat {
+	if a.excluded[strings.ToLower(name)] {
+		return nil
+	}
+	own := true
+	if alias, exists := a.aliases[strings.ToLower(name)]; exists {
+		name, own = alias, false
+	}
+	key := strings.ToLower(name)
+	if a.excluded[key] {
+		return nil
+	}
+
+	stat, exists := a.stats[key]
+	if !exists {
+		stat = &LanguageStat{Name: name, Color: colour}
+		a.stats[key] = stat
+	}
+	// A language keeps its own colour over the colour of an alias
+	if own && !a.coloured[key] {
+		stat.Name, stat.Color = name, colour
+		a.coloured[key] = true
+	}
+	return stat
+}
+
+// languages returns the languages above the threshold sorted by weight, with
+// their percentages renormalized to sum to 100%. Languages without weight,
+// like the secondary languages of repositories with the commits weighting,
+// are left out.
+func (a *languageAggregator) languages() []LanguageStat {
+	if a.totalWeight <= 0 {
+		return []LanguageStat{}
+	}
+
+	languages := []LanguageStat{}
+	for key, stat := range a.stats {
+		weight := a.weights[key]
+		stat.Percentage = weight / a.totalWeight * 100.0
+		if weight > 0 && stat.Percentage >= a.options.Threshold {
+			languages = append(languages, *stat)
+		}
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Percentage != languages[j].Percentage {
+			return languages[i].Percentage > languages[j].Percentage
+		}
+		return languages[i].Name < languages[j].Name
+	})
+	if a.options.Top > 0 && len(languages) > a.options.Top {
+		languages = languages[:a.options.Top]
+	}
+
+	// Renormalize percentages to sum to 100% after filtering
+	totalPercentage := 0.0
+	for _, lang := range languages {
+		totalPercentage += lang.Percentage
+	}
+	for i := range languages {
+		languages[i].Percentage = languages[i].Percentage / totalPercentage * 100.0
+	}
+	return languages
+}
+
+// LanguageTotals aggregates languages collected by data sources other than
+// GitHub, applying the exclusions, aliases, threshold and top-N of the
+// options. Data sources add either the lines added to files, weighted by
+// their number like the authored languages, or the sizes of the languages
+// of repositories, weighted by the weighting of the options.
+type LanguageTotals struct {
+	aggregator *languageAggregator
+}
+
+// NewLanguageTotals returns empty language totals aggregated with the options.
+// Other data sources have no commits of the user per repository, so the
+// commits weighting weighs by size like the bytes weighting.
+func NewLanguageTotals(options LanguageOptions) *LanguageTotals {
+	if options.Weighting == WeightingCommits {
+		options.Weighting = WeightingBytes
+	}
+	return &LanguageTotals{aggregator: newLanguageAggregator(options, time.Now())}
+}
+
+// AddRepository adds size bytes of the language in the repository named
+// repository, last pushed to at pushedAt
+func (t *LanguageTotals) AddRepository(
+	repository, name, colour string,
+	size int64,
+	pushedAt time.Time,
+) {
+	if size > 0 {
+		repo := languageRepository{Name: repository, PushedAt: pushedAt}
+		t.aggregator.add(repo, name, colour, size)
+	}
+}
+
+// AddFile adds lines added to the file at the slash separated path to the
+// language of the path. Files LanguageForPath finds no language for are
+// ignored.
+func (t *LanguageTotals) AddFile(filePath string, additions int64) {
+	if name, colour, ok := LanguageForPath(filePath); ok && additions > 0 {
+		t.aggregator.addAdditions(name, colour, additions)
+	}
+}
+
+// Languages returns the aggregated languages, the most used first
+func (t *LanguageTotals) Languages() []LanguageStat {
+	return t.aggregator.languages()
+}
@@ -0,0 +1,224 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+)
+
+func TestLanguageAggregatorAppliesOptions(t *testing.T) {
+	aggregator := newLanguageAggregator(LanguageOptions{
+		Exclude:   []string{"html", "Makefile"},
+		Aliases:   map[string]string{"tsx": "TypeScript", "Vue": "JavaScript"},
+		Threshold: 5,
+	}, time.Time{})
+	aggregator.add(languageRepository{}, "TSX", "#3178c6", 300)
+	aggregator.add(languageRepository{}, "TypeScript", "#2b7489", 200)
+	aggregator.add(languageRepository{}, "Vue", "#41b883", 100)
+	aggregator.add(languageRepository{}, "HTML", "#e34c26", 1000)
+	aggregator.add(languageRepository{}, "Makefile", "#427819", 10)
+	aggregator.add(languageRepository{}, "Shell", "#89e051", 20)
+	aggregator.add(languageRepository{}, "Go", "#00ADD8", 380)
+
+	languages := aggregator.languages()
+
+	want := []LanguageStat{
+		{Name: "TypeScript", Color: "#2b7489", TotalBytes: 500},
+		{Name: "Go", Color: "#00ADD8", TotalBytes: 380},
+		{Name: "JavaScript", Color: "#41b883", TotalBytes: 100},
+	}
+	if len(languages) != len(want) {
+		t.Fatalf("expected %d languages, got %+v", len(want), languages)
+	}
+	total := 0.0
+	for i, lang := range languages {
+		total += lang.Percentage
+		lang.Percentage = 0
+		if lang != want[i] {
+			t.Fatalf("expected language %d to be %+v, got %+v", i, want[i], lang)
+		}
+	}
+	if math.Abs(total-100) > 1e-9 {
+		t.Fatalf("expected percentages to sum to 100, got %f", total)
+	}
+}
+
+func TestLanguageAggregatorKeepsTopLanguages(t *testing.T) {
+	aggregator := newLanguageAggregator(LanguageOptions{Top: 2}, time.Time{})
+	aggregator.add(languageRepository{}, "Go", "", 50)
+	aggregator.add(languageRepository{}, "Shell", "", 1)
+	aggregator.add(languageRepository{}, "Python", "", 30)
+	aggregator.add(languageRepository{}, "C", "", 30)
+
+	languages := aggregator.languages()
+
+	if len(languages) != 2 || languages[0].Name != "Go" || languages[1].Name != "C" {
+		t.Fatalf("expected the two largest languages ordered by size then name, got %+v", languages)
+	}
+	if math.Abs(languages[0].Percentage-62.5) > 1e-9 {
+		t.Fatalf("expected percentages renormalized over the top languages, got %+v", languages)
+	}
+}
+
+func TestParseLanguageAliases(t *testing.T) {
+	aliases, err := ParseLanguageAliases("TSX=TypeScript, Vue = JavaScript\nSCSS=CSS,")
+	if err != nil {
+		t.Fatalf("expected aliases to be parsed, got %v", err)
+	}
+	want := map[string]string{"TSX": "TypeScript", "Vue": "JavaScript", "SCSS": "CSS"}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Fatalf("expected %v, got %v", want, aliases)
+	}
+
+	if _, err := ParseLanguageAliases("TSX"); err == nil {
+		t.Fatalf("expected an error for an alias without a language")
+	}
+	if got := ParseList(" HTML,CSS\n\nJupyter Notebook "); !reflect.DeepEqual(
+		got,
+		[]string{"HTML", "CSS", "Jupyter Notebook"},
+	) {
+		t.Fatalf("unexpected language list %q", got)
+	}
+}
+
+func TestGetLanguageStatsFetchesEveryLanguagePage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var request githubapi.GitHubGraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if strings.Contains(request.Query, "repository(owner") {
+			if request.Variables["owner"] != "octocat" || request.Variables["after"] != "page1" {
+				t.Errorf("unexpected repository variables %v", request.Variables)
+			}
+			_, _ = w.Write([]byte(`{"data": {"repository": {"languages": {
+				"pageInfo": {"hasNextPage": false, "endCursor": "page2"},
+				"edges": [{"size": 100, "node": {"name": "Shell", "color": "#89e051"}}]
+			}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"user": {"repositories": {
+			"pageInfo": {"hasNextPage": false, "endCursor": "repos"},
+			"nodes": [{"name": "hello", "owner": {"login": "octocat"}, "languages": {
+				"pageInfo": {"hasNextPage": true, "endCursor": "page1"},
+				"edges": [{"size": 300, "node": {"name": "Go", "color": "#00ADD8"}}]
+			}}]
+		}}}}`))
+	}))
+	defer server.Close()
+
+	collector := NewCollector("token")
+	collector.GraphQL.Endpoint = server.URL
+	languages, err := collector.getLanguageStats(context.Background(), "octocat", "id")
+	if err != nil {
+		t.Fatalf("expected language statistics, got %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected the second languages page to be fetched, got %d requests", requests)
+	}
+	if len(languages) != 2 || languages[1].Name != "Shell" || languages[1].Percentage != 25 {
+		t.Fatalf("expected the languages of both pages, got %+v", languages)
+	}
+}
+
+func TestLanguageAggregatorWeightings(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	generated := languageRepository{
+		Name:            "octocat/generated",
+		PushedAt:        now.Add(-2 * RecencyHalfLife),
+		PrimaryLanguage: "Java",
+		Commits:         5,
+	}
+	active := languageRepository{
+		Name:            "octocat/active",
+		PushedAt:        now,
+		PrimaryLanguage: "Go",
+		Commits:         15,
+	}
+	add := func(aggregator *languageAggregator) {
+		aggregator.add(generated, "Java", "", 900)
+		aggregator.add(generated, "Go", "", 100)
+		aggregator.add(active, "Go", "", 400)
+	}
+
+	for _, tc := range []struct {
+		weighting string
+		want      map[string]float64
+	}{
+		{WeightingBytes, map[string]float64{"Java": 900.0 / 14, "Go": 500.0 / 14}},
+		{"", map[string]float64{"Java": 900.0 / 14, "Go": 500.0 / 14}},
+		{WeightingRepositories, map[string]float64{"Go": 200.0 / 3, "Java": 100.0 / 3}},
+		// The generated repository was last pushed to two half-lives ago
+		{WeightingRecency, map[string]float64{"Java": 225 / 6.5, "Go": 425 / 6.5}},
+		{WeightingCommits, map[string]float64{"Go": 75, "Java": 25}},
+	} {
+		aggregator := newLanguageAggregator(LanguageOptions{Weighting: tc.weighting}, now)
+		add(aggregator)
+
+		languages := aggregator.languages()
+
+		if len(languages) != len(tc.want) {
+			t.Fatalf("expected %d languages for %q, got %+v", len(tc.want), tc.weighting, languages)
+		}
+		for i, lang := range languages {
+			if math.Abs(lang.Percentage-tc.want[lang.Name]) > 1e-9 {
+				t.Fatalf(
+					"expected %s at %g%% for %q, got %+v",
+					lang.Name,
+					tc.want[lang.Name],
+					tc.weighting,
+					languages,
+				)
+			}
+			if i > 0 && lang.Percentage > languages[i-1].Percentage {
+				t.Fatalf(
+					"expected languages sorted by weight for %q, got %+v",
+					tc.weighting,
+					languages,
+				)
+			}
+			if wantBytes := map[string]int64{"Java": 900, "Go": 500}[lang.Name]; lang.TotalBytes != wantBytes {
+				t.Fatalf(
+					"expected the size of %s to stay in bytes, got %d",
+					lang.Name,
+					lang.TotalBytes,
+				)
+			}
+		}
+	}
+
+	if err := (LanguageOptions{Weighting: "lines"}).Validate(); err == nil {
+		t.Fatalf("expected an error for an unknown weighting")
+	}
+}
+
+func TestLanguageAggregatorCountsRepositoriesOncePerAliasedLanguage(t *testing.T) {
+	aggregator := newLanguageAggregator(LanguageOptions{
+		Weighting: WeightingRepositories,
+		Aliases:   map[string]string{"JSX": "JavaScript"},
+	}, time.Time{})
+	app := languageRepository{Name: "octocat/app"}
+	aggregator.add(app, "JavaScript", "#f1e05a", 300)
+	aggregator.add(app, "JSX", "", 200)
+	aggregator.add(app, "CSS", "#563d7c", 100)
+	aggregator.add(languageRepository{Name: "octocat/site"}, "CSS", "#563d7c", 100)
+
+	languages := aggregator.languages()
+
+	if len(languages) != 2 || languages[0].Name != "CSS" ||
+		math.Abs(languages[0].Percentage-200.0/3) > 1e-9 ||
+		math.Abs(languages[1].Percentage-100.0/3) > 1e-9 || languages[1].TotalBytes != 500 {
+		t.Fatalf("expected the aliased languages to count the repository once, got %+v", languages)
+	}
+}
@@ -0,0 +1,179 @@
+// Package metrics collects a user's coding metrics from GitHub into a
+// versioned document that can be stored as JSON and rendered.
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+)
+
+// SchemaVersion is the version of the metrics document format. It is
+// incremented whenever a change would stop older documents from rendering.
+const SchemaVersion = 1
+
+// Metrics is the document the SVG is rendered from. It is collected from GitHub
+// by a Collector, can be written to and read from JSON, and is the only input
+// of the renderer, so it can be cached, edited by hand or assembled from other
+// sources. See docs/METRICS.md for the JSON format.
+type Metrics struct {
+	SchemaVersion int                   `json:"schema_version"`
+	GeneratedAt   time.Time             `json:"generated_at"`
+	User          *GitHubUserInfo       `json:"user"`
+	Totals        *GitHubTotalsStats    `json:"totals"`
+	Languages     []LanguageStat        `json:"languages"`
+	Calendar      *ContributionCalendar `json:"calendar"`
+}
+
+// Provider collects metrics documents from a data source. *Collector collects
+// them from GitHub.
+type Provider interface {
+	// Collect collects the metrics of the user the provider is configured for
+	Collect(ctx context.Context) (*Metrics, error)
+	// CollectUser collects the metrics of the user with the given login
+	CollectUser(ctx context.Context, login string) (*Metrics, error)
+}
+
+// ErrUserNotFound is returned, wrapped, by providers for logins without a
+// user
+var ErrUserNotFound = errors.New("user not found")
+
+// Collector collects metrics from the GitHub APIs
+type Collector struct {
+	GraphQL *githubapi.GitHubGraphQLClient
+	REST    *githubapi.GitHubRESTClient
+	// HTTPClient is used to download the avatar embedded in the document
+	HTTPClient *http.Client
+	// Languages configures how the languages of the repositories are
+	// aggregated
+	Languages LanguageOptions
+	// Repositories selects the repositories the commits and languages are
+	// aggregated over
+	Repositories RepositoryFilter
+}
+
+// NewCollector creates a Collector authenticated with the given token
+func NewCollector(token string) *Collector {
+	return &Collector{
+		GraphQL:    githubapi.NewGitHubGraphQLClient(token),
+		REST:       githubapi.NewGitHubRESTClient(token),
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+		Languages:  LanguageOptions{Threshold: DefaultLanguageThreshold},
+	}
+}
+
+// Collect collects all metrics for the authenticated user
+func (c *Collector) Collect(ctx context.Context) (*Metrics, error) {
+	userInfo, err := c.getGitHubUserInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.collect(ctx, userInfo)
+}
+
+// CollectUser collects all metrics for the user with the given login. Private
+// contributions are only included if the token belongs to that user.
+func (c *Collector) CollectUser(ctx context.Context, login string) (*Metrics, error) {
+	userInfo, err := c.getUser(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	return c.collect(ctx, userInfo)
+}
+
+// collect collects the remaining metrics of the user
+func (c *Collector) collect(ctx context.Context, userInfo *GitHubUserInfo) (*Metrics, error) {
+	userInfo.AvatarDataURI = c.getAvatarDataURI(ctx, userInfo.AvatarURL)
+	userId, err := c.getUserId(ctx, userInfo.Login)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := c.getGitHubTotalsStats(ctx, userInfo.Login, userId)
+	if err != nil {
+		return nil, err
+	}
+	var languages []LanguageStat
+	if c.Languages.Authored {
+		languages, err = c.getAuthoredLanguageStats(ctx, userInfo.Login)
+	} else {
+		languages, err = c.getLanguageStats(ctx, userInfo.Login, userId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := c.getContributionCalendar(ctx, userInfo.Login)
+	if err != nil {
+		return nil, err
+	}
+	return &Metrics{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		User:          userInfo,
+		Totals:        totals,
+		Languages:     languages,
+		Calendar:      calendar,
+	}, nil
+}
+
+// RenderTime returns the time relative dates in the SVG are computed from.
+// The generation time is used so that rendering a document is reproducible;
+// documents without one are rendered relative to the current time.
+func (m *Metrics) RenderTime() time.Time {
+	if m.GeneratedAt.IsZero() {
+		return time.Now().UTC()
+	}
+	return m.GeneratedAt
+}
+
+// Write writes the metrics as indented JSON
+func Write(w io.Writer, metrics *Metrics) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(metrics); err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	return nil
+}
+
+// Read reads and validates a metrics document
+func Read(r io.Reader) (*Metrics, error) {
+	var metrics Metrics
+	if err := json.NewDecoder(r).Decode(&metrics); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics: %w", err)
+	}
+	if err := metrics.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid metrics document: %w", err)
+	}
+	return &metrics, nil
+}
+
+// Validate checks the document has a supported version and the sections the
+// renderer requires.
+func (m *Metrics) Validate() error {
+	if m.SchemaVersion == 0 {
+		return errors.New("schema_version is required")
+	}
+	if m.SchemaVersion > SchemaVersion {
+		return fmt.Errorf(
+			"schema_version %d is newer than the supported version %d",
+			m.SchemaVersion,
+			SchemaVersion,
+		)
+	}
+	if m.User == nil {
+		return errors.New("user is required")
+	}
+	if m.Totals == nil {
+		return errors.New("totals is required")
+	}
+	if m.Calendar == nil {
+		return errors.New("calendar is required")
+	}
+	return nil
+}
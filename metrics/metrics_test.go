@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+func TestMetricsRoundTrip(t *testing.T) {
+	metrics := &Metrics{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+		User:          &GitHubUserInfo{Login: "octocat", Followers: 100},
+		Totals:        &GitHubTotalsStats{TotalCommits: 1200},
+		Languages:     []LanguageStat{{Name: "Go", Color: "#00ADD8", Percentage: 100}},
+		Calendar: &ContributionCalendar{
+			TotalContributions: 3,
+			Weeks: []ContributionWeek{{ContributionDays: []ContributionDay{
+				{Date: "2026-01-26", ContributionCount: 3, Color: theme.GitHubContribMediumLow},
+			}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, metrics); err != nil {
+		t.Fatalf("expected metrics to be written, got %v", err)
+	}
+	for _, want := range []string{`"schema_version": 1`, `"total_commits": 1200`, `"contribution_count": 3`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected metrics JSON to contain %q, got %s", want, buf.String())
+		}
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("expected metrics to be read, got %v", err)
+	}
+	if !got.GeneratedAt.Equal(metrics.GeneratedAt) || got.Totals.TotalCommits != 1200 ||
+		got.Calendar.Weeks[0].ContributionDays[0].ContributionCount != 3 {
+		t.Fatalf("expected metrics to round trip, got %+v", got)
+	}
+}
+
+func TestReadRejectsUnsupportedVersions(t *testing.T) {
+	for _, document := range []string{
+		`{"user":{},"totals":{},"calendar":{}}`,
+		`{"schema_version":999,"user":{},"totals":{},"calendar":{}}`,
+		`{"schema_version":1,"user":{},"calendar":{}}`,
+	} {
+		if _, err := Read(strings.NewReader(document)); err == nil {
+			t.Fatalf("expected invalid document to be rejected: %s", document)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Repository affiliations of the user selecting the repositories aggregated
+const (
+	AffiliationOwner              = "OWNER"
+	AffiliationOrganizationMember = "ORGANIZATION_MEMBER"
+	AffiliationCollaborator       = "COLLABORATOR"
+)
+
+// DefaultAffiliations are the affiliations used when a filter sets none
+var DefaultAffiliations = []string{
+	AffiliationOwner,
+	AffiliationOrganizationMember,
+	AffiliationCollaborator,
+}
+
+// RepositoryFilter selects the repositories the repository based metrics,
+// the commits and languages, are aggregated over
+type RepositoryFilter struct {
+	// Affiliations are the affiliations of the user with the repositories,
+	// DefaultAffiliations if empty
+	Affiliations    []string
+	ExcludeForks    bool
+	ExcludeArchived bool
+	ExcludePrivate  bool
+	// ExcludeOwners lists users and organizations whose repositories are
+	// left out, matched case-insensitively
+	ExcludeOwners []string
+	// ExcludeNames are path.Match globs matched case-insensitively against
+	// the name and the owner/name of the repositories left out
+	ExcludeNames []string
+	// ExcludeTopics lists topics of repositories that are left out
+	ExcludeTopics []string
+}
+
+// ParseList splits a comma or newline separated list, ignoring blank entries
+func ParseList(value string) []string {
+	items := []string{}
+	for _, item := range strings.FieldsFunc(value, isListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || r == '\n'
+}
+
+// Validate checks the affiliations and name globs of the filter
+func (f RepositoryFilter) Validate() error {
+	for _, affiliation := range f.Affiliations {
+		if !slices.Contains(DefaultAffiliations, affiliation) {
+			return fmt.Errorf(
+				"unknown repository affiliation %q, expected one of %s",
+				affiliation,
+				strings.Join(DefaultAffiliations, ", "),
+			)
+		}
+	}
+	for _, pattern := range f.ExcludeNames {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid repository name pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// affiliations returns the affiliations to query
+func (f RepositoryFilter) affiliations() []string {
+	if len(f.Affiliations) == 0 {
+		return DefaultAffiliations
+	}
+	return f.Affiliations
+}
+
+// repositoryFilterFields is a GraphQL fragment with the fields of a
+// repository the filter matches on, decoded into repositoryInfo
+const repositoryFilterFields = `
+	fragment RepositoryFilterFields on Repository {
+		name
+		owner {
+			login
+		}
+		isFork
+		isArchived
+		isPrivate
+		repositoryTopics(first: 20) {
+			nodes {
+				topic {
+					name
+				}
+			}
+		}
+	}`
+
+// repositoryInfo holds the fields of the RepositoryFilterFields fragment
+type repositoryInfo struct {
+	Name  string `json:"name"`
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	IsFork           bool `json:"isFork"`
+	IsArchived       bool `json:"isArchived"`
+	IsPrivate        bool `json:"isPrivate"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+}
+
+// Repository holds the fields of a repository of any data source a
+// RepositoryFilter matches on
+type Repository struct {
+	// Owner is the user, organization or namespace owning the repository
+	Owner    string
+	Name     string
+	Fork     bool
+	Archived bool
+	Private  bool
+	Topics   []string
+}
+
+// Matches reports whether the repository is aggregated. Affiliations are not
+// matched, they select the repositories listed.
+func (f RepositoryFilter) Matches(repo Repository) bool {
+	switch {
+	case f.ExcludeForks && repo.Fork,
+		f.ExcludeArchived && repo.Archived,
+		f.ExcludePrivate && repo.Private:
+		return false
+	}
+	if containsFold(f.ExcludeOwners, repo.Owner) {
+		return false
+	}
+	name := strings.ToLower(repo.Name)
+	fullName := strings.ToLower(repo.Owner + "/" + repo.Name)
+	for _, pattern := range f.ExcludeNames {
+		pattern = strings.ToLower(pattern)
+		if matched, _ := path.Match(pattern, name); matched {
+			return false
+		}
+		if matched, _ := path.Match(pattern, fullName); matched {
+			return false
+		}
+	}
+	for _, topic := range repo.Topics {
+		if containsFold(f.ExcludeTopics, topic) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the GitHub repository is aggregated
+func (f RepositoryFilter) matches(repo repositoryInfo) bool {
+	topics := []string{}
+	for _, node := range repo.RepositoryTopics.Nodes {
+		topics = append(topics, node.Topic.Name)
+	}
+	return f.Matches(Repository{
+		Owner:    repo.Owner.Login,
+		Name:     repo.Name,
+		Fork:     repo.IsFork,
+		Archived: repo.IsArchived,
+		Private:  repo.IsPrivate,
+		Topics:   topics,
+	})
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
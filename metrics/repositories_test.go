@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+)
+
+func testRepository(owner, name string, topics ...string) repositoryInfo {
+	repo := repositoryInfo{Name: name}
+	repo.Owner.Login = owner
+	for _, topic := range topics {
+		node := struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		}{}
+		node.Topic.Name = topic
+		repo.RepositoryTopics.Nodes = append(repo.RepositoryTopics.Nodes, node)
+	}
+	return repo
+}
+
+func TestRepositoryFilterMatches(t *testing.T) {
+	filter := RepositoryFilter{
+		ExcludeForks:    true,
+		ExcludeArchived: true,
+		ExcludePrivate:  true,
+		ExcludeOwners:   []string{"BigCorp"},
+		ExcludeNames:    []string{"*-vendored", "octocat/dotfiles"},
+		ExcludeTopics:   []string{"mirror"},
+	}
+	fork := testRepository("octocat", "fork")
+	fork.IsFork = true
+	archived := testRepository("octocat", "old")
+	archived.IsArchived = true
+	private := testRepository("octocat", "secret")
+	private.IsPrivate = true
+
+	for _, tc := range []struct {
+		repo repositoryInfo
+		want bool
+	}{
+		{testRepository("octocat", "hello"), true},
+		{fork, false},
+		{archived, false},
+		{private, false},
+		{testRepository("bigcorp", "monorepo"), false},
+		{testRepository("octocat", "libs-Vendored"), false},
+		{testRepository("octocat", "dotfiles"), false},
+		{testRepository("someone", "dotfiles"), true},
+		{testRepository("octocat", "linux", "Mirror"), false},
+		{testRepository("octocat", "tools", "go"), true},
+	} {
+		if got := filter.matches(tc.repo); got != tc.want {
+			t.Fatalf(
+				"expected %s/%s to match %v, got %v",
+				tc.repo.Owner.Login,
+				tc.repo.Name,
+				tc.want,
+				got,
+			)
+		}
+	}
+	if !(RepositoryFilter{}).matches(fork) {
+		t.Fatalf("expected an empty filter to match every repository")
+	}
+}
+
+func TestRepositoryFilterValidate(t *testing.T) {
+	if err := (RepositoryFilter{Affiliations: []string{"OWNER"}, ExcludeNames: []string{"a*"}}).Validate(); err != nil {
+		t.Fatalf("expected a valid filter, got %v", err)
+	}
+	if err := (RepositoryFilter{Affiliations: []string{"owner"}}).Validate(); err == nil {
+		t.Fatalf("expected an error for an unknown affiliation")
+	}
+	if err := (RepositoryFilter{ExcludeNames: []string{"[a"}}).Validate(); err == nil {
+		t.Fatalf("expected an error for an invalid glob")
+	}
+}
+
+func TestGetCommitsTotalFiltersRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request githubapi.GitHubGraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		affiliations, _ := request.Variables["affiliations"].([]interface{})
+		if !reflect.DeepEqual(affiliations, []interface{}{"OWNER"}) {
+			t.Errorf(
+				"expected the configured affiliations, got %v",
+				request.Variables["affiliations"],
+			)
+		}
+		_, _ = w.Write([]byte(`{"data": {"user": {"repositories": {
+			"pageInfo": {"hasNextPage": false, "endCursor": ""},
+			"nodes": [
+				{"name": "hello", "owner": {"login": "octocat"},
+					"stargazerCount": 9, "forkCount": 2,
+					"defaultBranchRef": {"target": {"history": {"totalCount": 10}}}},
+				{"name": "linux", "owner": {"login": "octocat"}, "isFork": true,
+					"defaultBranchRef": {"target": {"history": {"totalCount": 1000}}}}
+			]
+		}}}}`))
+	}))
+	defer server.Close()
+
+	collector := NewCollector("token")
+	collector.GraphQL.Endpoint = server.URL
+	collector.Repositories = RepositoryFilter{Affiliations: []string{"OWNER"}, ExcludeForks: true}
+	totals, err := collector.getRepositoryTotals(context.Background(), "octocat", "id")
+	if err != nil {
+		t.Fatalf("expected the commits total, got %v", err)
+	}
+
+	want := repositoryTotals{Commits: 10, Repositories: 1, Stargazers: 9, Forks: 2}
+	if *totals != want {
+		t.Fatalf("expected the fork to be left out, got %+v", *totals)
+	}
+}
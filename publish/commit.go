@@ -0,0 +1,306 @@
+// Package publish publishes rendered metrics cards to a repository, a gist or
+// a release.
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v61/github"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// Result describes the outcome of publishing the SVG
+type Result struct {
+	// CommitSHA is the SHA of the commit made, or of the branch head if the
+	// files were already up to date. It is empty for gists and releases.
+	CommitSHA string
+	// Changed reports whether anything was published
+	Changed bool
+	// URL is where the first file can be downloaded from
+	URL string
+}
+
+// NewGitHubClient creates a GitHub REST client authenticated with the given token.
+func NewGitHubClient(ctx context.Context, token string) *github.Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	tc := oauth2.NewClient(ctx, ts)
+	return github.NewClient(tc)
+}
+
+// SplitRepository splits an owner/repo string into its owner and repository name.
+func SplitRepository(ownerRepo string) (string, string, bool) {
+	parts := strings.Split(ownerRepo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// File is a file to publish
+type File struct {
+	// Name is the path of the file in the repository, or its name in a gist
+	// or release
+	Name    string
+	Content []byte
+}
+
+// CommitOptions configures committing the SVG to a repository
+type CommitOptions struct {
+	Owner    string
+	Repo     string
+	Branch   string
+	Message  string
+	Settings CommitSettings
+	// Readme updates the coding-metrics block of a README in the same commit
+	// when set
+	Readme *ReadmeOptions
+}
+
+// ReadmeOptions configures the block injected between the README markers
+type ReadmeOptions struct {
+	// Path is the path of the README in the repository
+	Path string
+	// DarkImage is the path or URL of the image shown in dark mode. The
+	// committed SVG is used if it is empty.
+	DarkImage string
+	// Alt is the alternative text of the image
+	Alt string
+	// Stats adds a table of the stats below the image when set
+	Stats *metrics.GitHubTotalsStats
+}
+
+// Commit commits the files to the repository in a single commit. If README
+// options are set, the README's coding-metrics block is updated in the same
+// commit to show the first file.
+func Commit(
+	ctx context.Context,
+	gh *github.Client,
+	files []File,
+	opts CommitOptions,
+) (Result, error) {
+	if len(files) == 0 {
+		return Result{}, errors.New("no files to commit")
+	}
+	// The contents API can only commit a single unsigned file, so use the Git
+	// Data API when committing several files, updating the README or signing
+	// the commit.
+	if len(files) > 1 || opts.Readme != nil || opts.Settings.Signer != nil {
+		contents := map[string][]byte{}
+		for _, file := range files {
+			contents[file.Name] = file.Content
+		}
+		if opts.Readme != nil {
+			readmePath := opts.Readme.Path
+			readme, err := getRepositoryFile(
+				ctx,
+				gh,
+				opts.Owner,
+				opts.Repo,
+				opts.Branch,
+				readmePath,
+			)
+			if err != nil {
+				return Result{}, fmt.Errorf("failed to get README file %s: %w", readmePath, err)
+			}
+			updatedReadme, err := updateReadmeContent(readme, files[0].Name, *opts.Readme)
+			if err != nil {
+				return Result{}, fmt.Errorf("failed to update README file %s: %w", readmePath, err)
+			}
+			contents[readmePath] = []byte(updatedReadme)
+		}
+		sha, changed, err := commitFiles(
+			ctx,
+			gh,
+			opts.Owner,
+			opts.Repo,
+			opts.Branch,
+			opts.Message,
+			contents,
+			opts.Settings,
+		)
+		if err != nil {
+			return Result{}, err
+		}
+		return Result{
+			CommitSHA: sha,
+			Changed:   changed,
+			URL:       rawFileURL(opts.Owner, opts.Repo, sha, files[0].Name),
+		}, nil
+	}
+
+	path, content := files[0].Name, files[0].Content
+	// Get current file SHA (omit if creating a new file)
+	fileContent, _, resp, err := gh.Repositories.GetContents(
+		ctx,
+		opts.Owner,
+		opts.Repo,
+		path,
+		&github.RepositoryContentGetOptions{Ref: opts.Branch},
+	)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return Result{}, fmt.Errorf("failed to get SVG file %s: %w", path, err)
+	}
+	var sha *string
+	if fileContent != nil {
+		if existing, err := fileContent.GetContent(); err == nil &&
+			existing == string(content) {
+			zap.L().Info("SVG file is already up to date", zap.String("path", path))
+			ref, _, err := gh.Git.GetRef(ctx, opts.Owner, opts.Repo, "heads/"+opts.Branch)
+			if err != nil {
+				return Result{}, fmt.Errorf("failed to get branch reference: %w", err)
+			}
+			head := ref.GetObject().GetSHA()
+			return Result{
+				CommitSHA: head,
+				Changed:   false,
+				URL:       rawFileURL(opts.Owner, opts.Repo, head, path),
+			}, nil
+		}
+		sha = fileContent.SHA
+	}
+
+	fileOpts := &github.RepositoryContentFileOptions{
+		Message: github.String(opts.Message),
+		Content: content,
+		SHA:     sha, // nil if new file
+		Branch:  github.String(opts.Branch),
+		// Author/Committer are nil unless configured, to get a bot-verified signature
+		Author:    opts.Settings.Author,
+		Committer: opts.Settings.Committer,
+	}
+	response, _, err := gh.Repositories.CreateFile(ctx, opts.Owner, opts.Repo, path, fileOpts)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to upload SVG file: %w", err)
+	}
+	commitSHA := response.Commit.GetSHA()
+	return Result{
+		CommitSHA: commitSHA,
+		Changed:   true,
+		URL:       rawFileURL(opts.Owner, opts.Repo, commitSHA, path),
+	}, nil
+}
+
+// rawFileURL returns the URL of the raw content of the file at the path of
+// the repository at the ref
+func rawFileURL(owner, repo, ref, path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf(
+		"https://raw.githubusercontent.com/%s/%s/%s/%s",
+		owner,
+		repo,
+		url.PathEscape(ref),
+		strings.Join(segments, "/"),
+	)
+}
+
+// updateReadmeContent injects the image tag, and the stats table if set,
+// between the README markers.
+func updateReadmeContent(readme, svgPath string, opts ReadmeOptions) (string, error) {
+	lightImage, err := readmeImagePath(opts.Path, svgPath)
+	if err != nil {
+		return "", err
+	}
+	darkImage := ""
+	if opts.DarkImage != "" {
+		darkImage, err = readmeImagePath(opts.Path, opts.DarkImage)
+		if err != nil {
+			return "", err
+		}
+	}
+	return injectReadmeBlock(readme, buildReadmeBlock(lightImage, darkImage, opts.Alt, opts.Stats))
+}
+
+// getRepositoryFile returns the decoded content of a file on the given branch.
+func getRepositoryFile(
+	ctx context.Context,
+	gh *github.Client,
+	owner, repo, branch, path string,
+) (string, error) {
+	fileContent, _, _, err := gh.Repositories.GetContents(
+		ctx,
+		owner,
+		repo,
+		path,
+		&github.RepositoryContentGetOptions{Ref: branch},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file contents: %w", err)
+	}
+	if fileContent == nil {
+		return "", fmt.Errorf("%s is not a file", path)
+	}
+	return fileContent.GetContent()
+}
+
+// commitFiles commits all files to the branch in a single commit using the Git
+// Data API and returns the SHA of the new commit. If the files are unchanged
+// no commit is made and the SHA of the current branch head is returned.
+func commitFiles(
+	ctx context.Context,
+	gh *github.Client,
+	owner, repo, branch, message string,
+	files map[string][]byte,
+	settings CommitSettings,
+) (string, bool, error) {
+	ref, _, err := gh.Git.GetRef(ctx, owner, repo, "heads/"+branch)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get branch reference: %w", err)
+	}
+	parent, _, err := gh.Git.GetCommit(ctx, owner, repo, ref.GetObject().GetSHA())
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get parent commit: %w", err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	entries := make([]*github.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entries = append(entries, &github.TreeEntry{
+			Path:    github.String(path),
+			Mode:    github.String("100644"),
+			Type:    github.String("blob"),
+			Content: github.String(string(files[path])),
+		})
+	}
+	tree, _, err := gh.Git.CreateTree(ctx, owner, repo, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create tree: %w", err)
+	}
+	if tree.GetSHA() == parent.GetTree().GetSHA() {
+		zap.L().Info("Files are already up to date", zap.Strings("paths", paths))
+		return parent.GetSHA(), false, nil
+	}
+
+	commit, _, err := gh.Git.CreateCommit(ctx, owner, repo, &github.Commit{
+		Message:   github.String(message),
+		Tree:      tree,
+		Parents:   []*github.Commit{{SHA: parent.SHA}},
+		Author:    settings.Author,
+		Committer: settings.Committer,
+	}, &github.CreateCommitOptions{Signer: settings.Signer})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	ref.Object.SHA = commit.SHA
+	if _, _, err := gh.Git.UpdateRef(ctx, owner, repo, ref, false); err != nil {
+		return "", false, fmt.Errorf("failed to update branch reference: %w", err)
+	}
+	zap.L().Info("Committed files", zap.String("sha", commit.GetSHA()), zap.Strings("paths", paths))
+	return commit.GetSHA(), true, nil
+}
@@ -0,0 +1,157 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/google/go-github/v61/github"
+)
+
+// CommitSettings holds the optional identity and signing configuration used
+// when creating commits. Nil fields fall back to GitHub's defaults.
+type CommitSettings struct {
+	Author    *github.CommitAuthor
+	Committer *github.CommitAuthor
+	Signer    github.MessageSigner
+}
+
+var trailerPattern = regexp.MustCompile(`^[A-Za-z0-9-]+: .+$`)
+
+// NewCommitSettings returns the settings for commits made with the given
+// author and committer. If an armored signing key is given, commits are signed
+// with it, which requires an author.
+func NewCommitSettings(
+	author, committer *github.CommitAuthor,
+	signingKey, passphrase string,
+) (CommitSettings, error) {
+	settings := CommitSettings{Author: author, Committer: committer}
+	if signingKey == "" {
+		return settings, nil
+	}
+	if settings.Author == nil {
+		return CommitSettings{}, errors.New(
+			"commit author name and email are required to sign commits",
+		)
+	}
+	signer, err := NewCommitSigner(signingKey, passphrase)
+	if err != nil {
+		return CommitSettings{}, err
+	}
+	settings.Signer = signer
+
+	// The signed payload includes the author and committer dates, so they
+	// must be fixed before the commit is created.
+	now := github.Timestamp{Time: time.Now().UTC().Truncate(time.Second)}
+	settings.Author.Date = &now
+	if settings.Committer != nil {
+		settings.Committer.Date = &now
+	}
+	return settings, nil
+}
+
+// CommitIdentity returns a commit author for the name and email, or nil if
+// neither is set.
+func CommitIdentity(name, email string) *github.CommitAuthor {
+	if name == "" && email == "" {
+		return nil
+	}
+	identity := &github.CommitAuthor{}
+	if name != "" {
+		identity.Name = github.String(name)
+	}
+	if email != "" {
+		identity.Email = github.String(email)
+	}
+	return identity
+}
+
+// NewCommitSigner returns a signer producing an armored detached OpenPGP
+// signature with the given armored private key.
+func NewCommitSigner(armoredKey, passphrase string) (github.MessageSigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, errors.New("signing key does not contain a private key")
+	}
+	entity := entities[0]
+
+	if entity.PrivateKey.Encrypted {
+		if passphrase == "" {
+			return nil, errors.New("signing key is encrypted but no passphrase was provided")
+		}
+		if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+		}
+	}
+
+	return github.MessageSignerFunc(func(w io.Writer, r io.Reader) error {
+		return openpgp.ArmoredDetachSign(w, entity, r, nil)
+	}), nil
+}
+
+// ParseCommitTrailers splits newline separated "Key: value" trailers,
+// ignoring blank lines.
+func ParseCommitTrailers(value string) ([]string, error) {
+	trailers := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !trailerPattern.MatchString(line) {
+			return nil, fmt.Errorf("invalid commit trailer %q, expected \"Key: value\"", line)
+		}
+		trailers = append(trailers, line)
+	}
+	return trailers, nil
+}
+
+// AppendCommitTrailers appends the trailers to the commit message, joining an
+// existing trailer block rather than starting a new paragraph and skipping
+// trailers the message already contains.
+func AppendCommitTrailers(message string, trailers []string) string {
+	message = strings.TrimRight(message, "\n")
+	lines := strings.Split(message, "\n")
+
+	missing := []string{}
+	for _, trailer := range trailers {
+		found := false
+		for _, line := range lines {
+			if strings.TrimSpace(line) == trailer {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, trailer)
+		}
+	}
+	if len(missing) == 0 {
+		return message
+	}
+
+	paragraphs := strings.Split(message, "\n\n")
+	lastParagraph := paragraphs[len(paragraphs)-1]
+	separator := "\n\n"
+	if len(paragraphs) > 1 && isTrailerBlock(lastParagraph) {
+		separator = "\n"
+	}
+	return message + separator + strings.Join(missing, "\n")
+}
+
+// isTrailerBlock reports whether every line of the paragraph is a trailer
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !trailerPattern.MatchString(strings.TrimSpace(line)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package publish
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+func TestAppendCommitTrailersStartsNewParagraph(t *testing.T) {
+	got := AppendCommitTrailers(
+		"Update Coding Metrics",
+		[]string{"Signed-off-by: Bot <bot@example.com>"},
+	)
+
+	want := "Update Coding Metrics\n\nSigned-off-by: Bot <bot@example.com>"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestAppendCommitTrailersJoinsExistingTrailers(t *testing.T) {
+	message := "Update Coding Metrics\n\nCo-authored-by: Someone <someone@example.com>\n"
+
+	got := AppendCommitTrailers(message, []string{
+		"Co-authored-by: Someone <someone@example.com>",
+		"Signed-off-by: Bot <bot@example.com>",
+	})
+
+	want := "Update Coding Metrics\n\nCo-authored-by: Someone <someone@example.com>\nSigned-off-by: Bot <bot@example.com>"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestParseCommitTrailersRejectsInvalidLines(t *testing.T) {
+	trailers, err := ParseCommitTrailers("Signed-off-by: Bot <bot@example.com>\n\n")
+	if err != nil || len(trailers) != 1 {
+		t.Fatalf("expected a single trailer, got %v (%v)", trailers, err)
+	}
+
+	if _, err := ParseCommitTrailers("not a trailer"); err == nil {
+		t.Fatalf("expected invalid trailer to return an error")
+	}
+}
+
+func TestNewCommitSettingsRequiresAuthorForSigning(t *testing.T) {
+	if _, err := NewCommitSettings(nil, nil, "key", ""); err == nil {
+		t.Fatalf("expected signing without an author to return an error")
+	}
+}
+
+func TestNewCommitSignerProducesVerifiableSignature(t *testing.T) {
+	entity, err := openpgp.NewEntity("Bot", "", "bot@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to create test key: %v", err)
+	}
+	var armoredKey bytes.Buffer
+	writer, err := armor.Encode(&armoredKey, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed to armor test key: %v", err)
+	}
+	if err := entity.SerializePrivate(writer, nil); err != nil {
+		t.Fatalf("failed to serialize test key: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close armor writer: %v", err)
+	}
+
+	signer, err := NewCommitSigner(armoredKey.String(), "")
+	if err != nil {
+		t.Fatalf("expected signer to be created, got %v", err)
+	}
+	var signature bytes.Buffer
+	if err := signer.Sign(&signature, strings.NewReader("commit payload")); err != nil {
+		t.Fatalf("expected payload to be signed, got %v", err)
+	}
+
+	_, err = openpgp.CheckArmoredDetachedSignature(
+		openpgp.EntityList{entity},
+		strings.NewReader("commit payload"),
+		&signature,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("expected signature to verify, got %v", err)
+	}
+}
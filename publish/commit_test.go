@@ -0,0 +1,84 @@
+package publish
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+)
+
+func TestRawFileURLEscapesPath(t *testing.T) {
+	got := rawFileURL("owner", "repo", "main", "cards/my metrics.svg")
+
+	want := "https://raw.githubusercontent.com/owner/repo/main/cards/my%20metrics.svg"
+	if got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestCommitReturnsBranchHeadWhenUpToDate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /repos/owner/repo/contents/metrics.svg",
+		func(w http.ResponseWriter, r *http.Request) {
+			content := base64.StdEncoding.EncodeToString([]byte("<svg/>"))
+			_, _ = w.Write([]byte(`{"type":"file","encoding":"base64","sha":"f1","content":"` +
+				content + `"}`))
+		},
+	)
+	mux.HandleFunc(
+		"GET /repos/owner/repo/git/ref/heads/main",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"head"}}`))
+		},
+	)
+	mux.HandleFunc(
+		"PUT /repos/owner/repo/contents/metrics.svg",
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Fatalf("expected the unchanged file not to be committed")
+		},
+	)
+
+	gh := newTestGitHubClient(t, mux)
+	result, err := Commit(
+		context.Background(),
+		gh,
+		[]File{{Name: "metrics.svg", Content: []byte("<svg/>")}},
+		CommitOptions{Owner: "owner", Repo: "repo", Branch: "main", Message: "Update"},
+	)
+	if err != nil {
+		t.Fatalf("expected the file to be up to date, got %v", err)
+	}
+	if result.Changed || result.CommitSHA != "head" ||
+		result.URL != "https://raw.githubusercontent.com/owner/repo/head/metrics.svg" {
+		t.Fatalf("expected the branch head, got %+v", result)
+	}
+}
+
+func TestCommitReportsContentsErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /repos/owner/repo/contents/metrics.svg",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+		},
+	)
+	mux.HandleFunc(
+		"PUT /repos/owner/repo/contents/metrics.svg",
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Fatalf("expected no file to be created after an error")
+		},
+	)
+
+	gh := newTestGitHubClient(t, mux)
+	_, err := Commit(
+		context.Background(),
+		gh,
+		[]File{{Name: "metrics.svg", Content: []byte("<svg/>")}},
+		CommitOptions{Owner: "owner", Repo: "repo", Branch: "main", Message: "Update"},
+	)
+	if err == nil {
+		t.Fatalf("expected the error of the contents API")
+	}
+}
@@ -0,0 +1,79 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v61/github"
+	"go.uber.org/zap"
+)
+
+// Gist creates or updates the files in the gist. Other files in the gist are
+// left untouched.
+func Gist(ctx context.Context, gh *github.Client, gistID string, files []File) (Result, error) {
+	if gistID == "" {
+		return Result{}, errors.New("a gist ID is required when publishing to a gist")
+	}
+	owner, changed, err := updateGistFiles(ctx, gh, gistID, files)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to upload SVG file to gist: %w", err)
+	}
+	result := Result{Changed: changed}
+	if len(files) > 0 {
+		result.URL = fmt.Sprintf(
+			"https://gist.githubusercontent.com/%s/%s/raw/%s",
+			owner,
+			gistID,
+			url.PathEscape(files[0].Name),
+		)
+	}
+	return result, nil
+}
+
+// updateGistFiles creates the files in the gist, or replaces their content if
+// they already exist. Unchanged files are skipped, and the gist is only edited
+// if at least one file changed. It returns the login of the owner of the gist
+// and reports whether the gist was changed.
+func updateGistFiles(
+	ctx context.Context,
+	gh *github.Client,
+	gistID string,
+	files []File,
+) (string, bool, error) {
+	gist, resp, err := gh.Gists.Get(ctx, gistID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", false, fmt.Errorf("gist %s not found", gistID)
+		}
+		return "", false, fmt.Errorf("failed to get gist: %w", err)
+	}
+
+	update := &github.Gist{Files: map[github.GistFilename]github.GistFile{}}
+	for _, file := range files {
+		filename := github.GistFilename(file.Name)
+		if existing, exists := gist.Files[filename]; exists {
+			if existing.GetContent() == string(file.Content) {
+				zap.L().Info("Gist file is already up to date", zap.String("file", file.Name))
+				continue
+			}
+			zap.L().
+				Info("Updating gist file", zap.String("gist", gistID), zap.String("file", file.Name))
+		} else {
+			zap.L().
+				Info("Creating gist file", zap.String("gist", gistID), zap.String("file", file.Name))
+		}
+		update.Files[filename] = github.GistFile{Content: github.String(string(file.Content))}
+	}
+	owner := gist.GetOwner().GetLogin()
+	if len(update.Files) == 0 {
+		return owner, false, nil
+	}
+
+	if _, _, err := gh.Gists.Edit(ctx, gistID, update); err != nil {
+		return "", false, fmt.Errorf("failed to edit gist: %w", err)
+	}
+	return owner, true, nil
+}
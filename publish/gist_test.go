@@ -0,0 +1,117 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v61/github"
+)
+
+func newTestGitHubClient(t *testing.T, handler http.Handler) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	gh := github.NewClient(server.Client())
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+	gh.BaseURL = baseURL
+	gh.UploadURL = baseURL
+	return gh
+}
+
+func TestUpdateGistFilesCreatesFile(t *testing.T) {
+	edited := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /gists/abc123", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(
+			[]byte(
+				`{"id":"abc123","owner":{"login":"octocat"},"files":{"other.md":{"content":"hello"}}}`,
+			),
+		)
+	})
+	mux.HandleFunc("PATCH /gists/abc123", func(w http.ResponseWriter, r *http.Request) {
+		edited = true
+		var gist github.Gist
+		if err := json.NewDecoder(r.Body).Decode(&gist); err != nil {
+			t.Fatalf("failed to decode gist edit: %v", err)
+		}
+		file, exists := gist.Files["metrics.svg"]
+		if !exists {
+			t.Fatalf("expected metrics.svg in gist edit, got %v", gist.Files)
+		}
+		if got := file.GetContent(); got != "<svg/>" {
+			t.Fatalf("expected SVG content in gist edit, got %q", got)
+		}
+		if _, exists := gist.Files["other.md"]; exists {
+			t.Fatalf("expected other gist files to be left untouched")
+		}
+		_, _ = w.Write([]byte(`{"id":"abc123"}`))
+	})
+
+	gh := newTestGitHubClient(t, mux)
+	owner, changed, err := updateGistFiles(
+		context.Background(),
+		gh,
+		"abc123",
+		[]File{{Name: "metrics.svg", Content: []byte("<svg/>")}},
+	)
+	if err != nil {
+		t.Fatalf("expected gist update to succeed, got %v", err)
+	}
+	if !edited || !changed {
+		t.Fatalf("expected gist to be edited")
+	}
+	if owner != "octocat" {
+		t.Fatalf("expected the owner of the gist, got %q", owner)
+	}
+}
+
+func TestUpdateGistFilesSkipsUnchangedFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /gists/abc123", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"abc123","files":{"metrics.svg":{"content":"<svg/>"}}}`))
+	})
+	mux.HandleFunc("PATCH /gists/abc123", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("expected unchanged gist file not to be edited")
+	})
+
+	gh := newTestGitHubClient(t, mux)
+	_, changed, err := updateGistFiles(
+		context.Background(),
+		gh,
+		"abc123",
+		[]File{{Name: "metrics.svg", Content: []byte("<svg/>")}},
+	)
+	if err != nil {
+		t.Fatalf("expected gist update to succeed, got %v", err)
+	}
+	if changed {
+		t.Fatalf("expected unchanged gist file to be reported as unchanged")
+	}
+}
+
+func TestUpdateGistFilesMissingGist(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /gists/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	})
+
+	gh := newTestGitHubClient(t, mux)
+	_, _, err := updateGistFiles(
+		context.Background(),
+		gh,
+		"missing",
+		[]File{{Name: "metrics.svg", Content: []byte("<svg/>")}},
+	)
+	if err == nil {
+		t.Fatalf("expected missing gist to return an error")
+	}
+}
@@ -0,0 +1,120 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"path"
+	"strings"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+const (
+	readmeStartMarker = "<!-- coding-metrics:start -->"
+	readmeEndMarker   = "<!-- coding-metrics:end -->"
+)
+
+// injectReadmeBlock replaces everything between the coding-metrics markers in
+// the Markdown document with the given block, keeping the markers themselves.
+func injectReadmeBlock(readme, block string) (string, error) {
+	start := strings.Index(readme, readmeStartMarker)
+	if start == -1 {
+		return "", fmt.Errorf("start marker %q not found", readmeStartMarker)
+	}
+	contentStart := start + len(readmeStartMarker)
+	end := strings.Index(readme[contentStart:], readmeEndMarker)
+	if end == -1 {
+		return "", fmt.Errorf("end marker %q not found after start marker", readmeEndMarker)
+	}
+	end += contentStart
+
+	return readme[:contentStart] + "\n" + block + "\n" + readme[end:], nil
+}
+
+// buildReadmeBlock builds the Markdown inserted between the README markers: a
+// <picture> element with light and dark sources and, if stats are provided, a
+// plain-text table of the same stats.
+func buildReadmeBlock(
+	lightImage, darkImage, alt string,
+	stats *metrics.GitHubTotalsStats,
+) string {
+	if darkImage == "" {
+		darkImage = lightImage
+	}
+
+	var b strings.Builder
+	b.WriteString("<picture>\n")
+	fmt.Fprintf(
+		&b,
+		"  <source media=\"(prefers-color-scheme: dark)\" srcset=\"%s\">\n",
+		html.EscapeString(darkImage),
+	)
+	fmt.Fprintf(
+		&b,
+		"  <source media=\"(prefers-color-scheme: light)\" srcset=\"%s\">\n",
+		html.EscapeString(lightImage),
+	)
+	fmt.Fprintf(
+		&b,
+		"  <img alt=\"%s\" src=\"%s\">\n",
+		html.EscapeString(alt),
+		html.EscapeString(lightImage),
+	)
+	b.WriteString("</picture>")
+
+	if stats != nil {
+		b.WriteString("\n\n")
+		b.WriteString(StatsMarkdownTable(stats))
+	}
+	return b.String()
+}
+
+// StatsMarkdownTable renders the GitHub totals as a Markdown table
+func StatsMarkdownTable(stats *metrics.GitHubTotalsStats) string {
+	rows := []struct {
+		label string
+		value int
+	}{
+		{"Commits", stats.TotalCommits},
+		{"Pull requests opened", stats.TotalPullRequests},
+		{"Pull requests reviewed", stats.TotalPullRequestReviews},
+		{"Issues opened", stats.TotalIssues},
+		{"Starred repositories", stats.TotalStarredRepos},
+		{"Sponsors", stats.TotalSponsors},
+		{"Organizations", stats.TotalMemberOfOrganizations},
+		{"Watchers", stats.TotalWatchers},
+	}
+
+	var b strings.Builder
+	b.WriteString("| Metric | Value |\n")
+	b.WriteString("| --- | ---: |\n")
+	for _, row := range rows {
+		fmt.Fprintf(&b, "| %s | %d |\n", row.label, row.value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// readmeImagePath returns the path of the image relative to the README so the
+// image tag resolves on GitHub. Absolute URLs are returned unchanged.
+func readmeImagePath(readmePath, imagePath string) (string, error) {
+	if imagePath == "" {
+		return "", errors.New("image path is empty")
+	}
+	if strings.Contains(imagePath, "://") {
+		return imagePath, nil
+	}
+
+	readmeDir := path.Dir(path.Clean("/" + readmePath))
+	image := path.Clean("/" + imagePath)
+	if readmeDir == "/" {
+		return strings.TrimPrefix(image, "/"), nil
+	}
+	if strings.HasPrefix(image, readmeDir+"/") {
+		return strings.TrimPrefix(image, readmeDir+"/"), nil
+	}
+
+	// The image is outside the README directory, so walk back up to the root.
+	depth := strings.Count(strings.TrimPrefix(readmeDir, "/"), "/") + 1
+	return strings.Repeat("../", depth) + strings.TrimPrefix(image, "/"), nil
+}
@@ -0,0 +1,154 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v61/github"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+func TestInjectReadmeBlockReplacesContentBetweenMarkers(t *testing.T) {
+	readme := "# Profile\n\n<!-- coding-metrics:start -->\nold content\n<!-- coding-metrics:end -->\n\nFooter\n"
+
+	got, err := injectReadmeBlock(readme, "new content")
+	if err != nil {
+		t.Fatalf("expected markers to be found, got %v", err)
+	}
+
+	want := "# Profile\n\n<!-- coding-metrics:start -->\nnew content\n<!-- coding-metrics:end -->\n\nFooter\n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestInjectReadmeBlockRequiresMarkers(t *testing.T) {
+	if _, err := injectReadmeBlock("# Profile\n", "content"); err == nil {
+		t.Fatalf("expected missing start marker to return an error")
+	}
+	if _, err := injectReadmeBlock("<!-- coding-metrics:end -->\n<!-- coding-metrics:start -->", "content"); err == nil {
+		t.Fatalf("expected end marker before start marker to return an error")
+	}
+}
+
+func TestBuildReadmeBlockIncludesPictureSourcesAndTable(t *testing.T) {
+	got := buildReadmeBlock(
+		"metrics.svg",
+		"metrics-dark.svg",
+		"GitHub stats",
+		&metrics.GitHubTotalsStats{TotalCommits: 42},
+	)
+
+	for _, want := range []string{
+		`<source media="(prefers-color-scheme: dark)" srcset="metrics-dark.svg">`,
+		`<source media="(prefers-color-scheme: light)" srcset="metrics.svg">`,
+		`<img alt="GitHub stats" src="metrics.svg">`,
+		"| Commits | 42 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected README block to contain %q, got %q", want, got)
+		}
+	}
+}
+
+func TestReadmeImagePath(t *testing.T) {
+	tests := []struct {
+		readme, image, want string
+	}{
+		{"README.md", "output.svg", "output.svg"},
+		{"README.md", "images/output.svg", "images/output.svg"},
+		{"docs/README.md", "docs/output.svg", "output.svg"},
+		{"docs/README.md", "output.svg", "../output.svg"},
+		{"README.md", "https://example.com/output.svg", "https://example.com/output.svg"},
+	}
+	for _, tt := range tests {
+		got, err := readmeImagePath(tt.readme, tt.image)
+		if err != nil {
+			t.Fatalf("expected image path for %q, got %v", tt.image, err)
+		}
+		if got != tt.want {
+			t.Fatalf("expected image path %q relative to %q, got %q", tt.want, tt.readme, got)
+		}
+	}
+}
+
+func TestCommitFilesCreatesSingleCommit(t *testing.T) {
+	var treeEntries []*github.TreeEntry
+	updated := false
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /repos/owner/repo/git/ref/heads/main",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"parent"}}`))
+		},
+	)
+	mux.HandleFunc(
+		"GET /repos/owner/repo/git/commits/parent",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"sha":"parent","tree":{"sha":"base-tree"}}`))
+		},
+	)
+	mux.HandleFunc(
+		"POST /repos/owner/repo/git/trees",
+		func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				BaseTree string              `json:"base_tree"`
+				Tree     []*github.TreeEntry `json:"tree"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode tree request: %v", err)
+			}
+			if body.BaseTree != "base-tree" {
+				t.Fatalf("expected base tree of parent commit, got %q", body.BaseTree)
+			}
+			treeEntries = body.Tree
+			_, _ = w.Write([]byte(`{"sha":"new-tree"}`))
+		},
+	)
+	mux.HandleFunc(
+		"POST /repos/owner/repo/git/commits",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"sha":"new-commit"}`))
+		},
+	)
+	mux.HandleFunc(
+		"PATCH /repos/owner/repo/git/refs/heads/main",
+		func(w http.ResponseWriter, r *http.Request) {
+			updated = true
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"new-commit"}}`))
+		},
+	)
+
+	gh := newTestGitHubClient(t, mux)
+	files := map[string][]byte{
+		"output.svg": []byte("<svg/>"),
+		"README.md":  []byte("# Profile"),
+	}
+	sha, changed, err := commitFiles(
+		context.Background(),
+		gh,
+		"owner",
+		"repo",
+		"main",
+		"Update",
+		files,
+		CommitSettings{},
+	)
+	if err != nil {
+		t.Fatalf("expected files to be committed, got %v", err)
+	}
+
+	if sha != "new-commit" || !changed {
+		t.Fatalf("expected new commit SHA, got %q (changed=%t)", sha, changed)
+	}
+	if len(treeEntries) != 2 {
+		t.Fatalf("expected both files in a single tree, got %d entries", len(treeEntries))
+	}
+	if !updated {
+		t.Fatalf("expected branch reference to be updated")
+	}
+}
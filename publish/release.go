@@ -0,0 +1,237 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-github/v61/github"
+	"go.uber.org/zap"
+)
+
+// DefaultReleaseTag is the tag of the release the SVG is uploaded to by default
+const DefaultReleaseTag = "metrics"
+
+// ReleaseOptions configures uploading the SVG as a release asset
+type ReleaseOptions struct {
+	Owner string
+	Repo  string
+	// Tag is the tag of the release, which is created if it does not exist
+	Tag string
+	// Branch is the target of the release tag when the release is created
+	Branch string
+}
+
+// Release uploads the files as assets of a rolling release, replacing any
+// existing assets with the same names. Assets whose content is unchanged are
+// left as they are. The content type of an asset is picked from the extension
+// of its name.
+func Release(
+	ctx context.Context,
+	gh *github.Client,
+	files []File,
+	opts ReleaseOptions,
+) (Result, error) {
+	tag := opts.Tag
+	if tag == "" {
+		tag = DefaultReleaseTag
+	}
+	release, err := getOrCreateRelease(ctx, gh, opts.Owner, opts.Repo, tag, opts.Branch)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to get release %s: %w", tag, err)
+	}
+	changed := false
+	for _, file := range files {
+		replaced, err := replaceReleaseAsset(ctx, gh, opts.Owner, opts.Repo, release, file)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to upload %s to release: %w", file.Name, err)
+		}
+		changed = changed || replaced
+	}
+	result := Result{Changed: changed}
+	if len(files) > 0 {
+		result.URL = fmt.Sprintf(
+			"https://github.com/%s/%s/releases/download/%s/%s",
+			opts.Owner,
+			opts.Repo,
+			url.PathEscape(tag),
+			url.PathEscape(files[0].Name),
+		)
+	}
+	return result, nil
+}
+
+// getOrCreateRelease returns the release for the given tag, creating it from
+// the target branch if it does not exist yet.
+func getOrCreateRelease(
+	ctx context.Context,
+	gh *github.Client,
+	owner, repo, tag, branch string,
+) (*github.RepositoryRelease, error) {
+	release, resp, err := gh.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+	if err == nil {
+		return release, nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return nil, fmt.Errorf("failed to get release by tag: %w", err)
+	}
+
+	zap.L().Info("Creating release", zap.String("tag", tag))
+	newRelease := &github.RepositoryRelease{
+		TagName: github.String(tag),
+		Name:    github.String("Coding Metrics"),
+		Body:    github.String("Generated coding metrics, updated on every run."),
+	}
+	if branch != "" {
+		newRelease.TargetCommitish = github.String(branch)
+	}
+	release, _, err = gh.Repositories.CreateRelease(ctx, owner, repo, newRelease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	return release, nil
+}
+
+// replaceReleaseAsset deletes any asset with the same name from the release
+// and uploads the file in its place, unless the existing asset has the same
+// content. It reports whether the asset was uploaded.
+func replaceReleaseAsset(
+	ctx context.Context,
+	gh *github.Client,
+	owner, repo string,
+	release *github.RepositoryRelease,
+	asset File,
+) (bool, error) {
+	assetName := asset.Name
+	// Deleting assets while paging shifts the later pages, so every asset
+	// with the name is collected before any is deleted
+	matching := []*github.ReleaseAsset{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		assets, resp, err := gh.Repositories.ListReleaseAssets(
+			ctx,
+			owner,
+			repo,
+			release.GetID(),
+			opts,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to list release assets: %w", err)
+		}
+		for _, existing := range assets {
+			if existing.GetName() == assetName {
+				matching = append(matching, existing)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	for _, existing := range matching {
+		unchanged, err := releaseAssetMatches(ctx, gh, owner, repo, existing, asset.Content)
+		if err != nil {
+			return false, err
+		}
+		if unchanged {
+			zap.L().Info("Release asset is already up to date", zap.String("asset", assetName))
+			return false, nil
+		}
+	}
+	for _, existing := range matching {
+		zap.L().Info("Deleting existing release asset", zap.String("asset", assetName))
+		if _, err := gh.Repositories.DeleteReleaseAsset(ctx, owner, repo, existing.GetID()); err != nil {
+			return false, fmt.Errorf("failed to delete release asset: %w", err)
+		}
+	}
+
+	// The upload needs a file to read the size from, so write the content to
+	// a temporary one.
+	file, err := os.CreateTemp("", "coding-metrics-asset-*")
+	if err != nil {
+		return false, fmt.Errorf("failed to create asset file: %w", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			zap.L().Warn("Failed to close asset file", zap.Error(cerr))
+		}
+		if rerr := os.Remove(file.Name()); rerr != nil {
+			zap.L().Warn("Failed to remove asset file", zap.Error(rerr))
+		}
+	}()
+	if _, err := file.Write(asset.Content); err != nil {
+		return false, fmt.Errorf("failed to write asset file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return false, fmt.Errorf("failed to rewind asset file: %w", err)
+	}
+
+	_, _, err = gh.Repositories.UploadReleaseAsset(
+		ctx,
+		owner,
+		repo,
+		release.GetID(),
+		&github.UploadOptions{
+			Name:      assetName,
+			MediaType: releaseAssetMediaType(assetName),
+		},
+		file,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to upload release asset: %w", err)
+	}
+	zap.L().Info("Uploaded release asset",
+		zap.String("release", release.GetTagName()),
+		zap.String("asset", assetName))
+	return true, nil
+}
+
+// releaseAssetMatches reports whether the existing release asset has the
+// content. The asset is only downloaded when its size matches.
+func releaseAssetMatches(
+	ctx context.Context,
+	gh *github.Client,
+	owner, repo string,
+	existing *github.ReleaseAsset,
+	content []byte,
+) (bool, error) {
+	if existing.GetSize() != len(content) {
+		return false, nil
+	}
+	rc, _, err := gh.Repositories.DownloadReleaseAsset(
+		ctx,
+		owner,
+		repo,
+		existing.GetID(),
+		gh.Client(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to download release asset: %w", err)
+	}
+	defer func() {
+		if cerr := rc.Close(); cerr != nil {
+			zap.L().Warn("Failed to close release asset", zap.Error(cerr))
+		}
+	}()
+	current, err := io.ReadAll(rc)
+	if err != nil {
+		return false, fmt.Errorf("failed to read release asset: %w", err)
+	}
+	return bytes.Equal(current, content), nil
+}
+
+// releaseAssetMediaType returns the content type of an asset from the
+// extension of its name, e.g. image/svg+xml or image/png
+func releaseAssetMediaType(name string) string {
+	if mediaType := mime.TypeByExtension(filepath.Ext(name)); mediaType != "" {
+		return mediaType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,203 @@
+package publish
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v61/github"
+)
+
+func TestPublishReleaseAssetCreatesReleaseAndReplacesAsset(t *testing.T) {
+	var created, deleted, uploaded bool
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /repos/owner/repo/releases/tags/metrics",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		},
+	)
+	mux.HandleFunc("POST /repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		created = true
+		_, _ = w.Write([]byte(`{"id":7,"tag_name":"metrics"}`))
+	})
+	mux.HandleFunc(
+		"GET /repos/owner/repo/releases/7/assets",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(
+				[]byte(`[{"id":1,"name":"other.svg"},{"id":2,"name":"metrics.svg","size":9}]`),
+			)
+		},
+	)
+	mux.HandleFunc(
+		"DELETE /repos/owner/repo/releases/assets/2",
+		func(w http.ResponseWriter, r *http.Request) {
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+	)
+	mux.HandleFunc(
+		"DELETE /repos/owner/repo/releases/assets/1",
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Fatalf("expected assets with other names to be kept")
+		},
+	)
+	mux.HandleFunc(
+		"POST /repos/owner/repo/releases/7/assets",
+		func(w http.ResponseWriter, r *http.Request) {
+			uploaded = true
+			if got := r.URL.Query().Get("name"); got != "metrics.svg" {
+				t.Fatalf("expected asset name metrics.svg, got %q", got)
+			}
+			if got := r.Header.Get("Content-Type"); got != "image/svg+xml" {
+				t.Fatalf("expected SVG content type, got %q", got)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != "<svg/>" {
+				t.Fatalf("expected SVG body, got %q", string(body))
+			}
+			_, _ = w.Write([]byte(`{"id":3,"name":"metrics.svg"}`))
+		},
+	)
+
+	gh := newTestGitHubClient(t, mux)
+	ctx := context.Background()
+	release, err := getOrCreateRelease(ctx, gh, "owner", "repo", "metrics", "main")
+	if err != nil {
+		t.Fatalf("expected release to be created, got %v", err)
+	}
+	asset := File{Name: "metrics.svg", Content: []byte("<svg/>")}
+	replaced, err := replaceReleaseAsset(ctx, gh, "owner", "repo", release, asset)
+	if err != nil || !replaced {
+		t.Fatalf("expected asset to be replaced, got %t, %v", replaced, err)
+	}
+
+	if !created || !deleted || !uploaded {
+		t.Fatalf(
+			"expected release to be created, asset deleted and uploaded, got created=%t deleted=%t uploaded=%t",
+			created,
+			deleted,
+			uploaded,
+		)
+	}
+}
+
+func TestGetOrCreateReleaseReusesExistingRelease(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /repos/owner/repo/releases/tags/metrics",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":9,"tag_name":"metrics"}`))
+		},
+	)
+	mux.HandleFunc("POST /repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("expected existing release to be reused")
+	})
+
+	gh := newTestGitHubClient(t, mux)
+	release, err := getOrCreateRelease(context.Background(), gh, "owner", "repo", "metrics", "main")
+	if err != nil {
+		t.Fatalf("expected existing release, got %v", err)
+	}
+	if release.GetID() != 9 {
+		t.Fatalf("expected release 9, got %d", release.GetID())
+	}
+}
+
+func TestReplaceReleaseAssetKeepsUnchangedAsset(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /repos/owner/repo/releases/7/assets",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"id":2,"name":"metrics.svg","size":6}]`))
+		},
+	)
+	mux.HandleFunc(
+		"GET /repos/owner/repo/releases/assets/2",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") != "application/octet-stream" {
+				t.Fatalf("expected the asset content to be downloaded")
+			}
+			_, _ = w.Write([]byte("<svg/>"))
+		},
+	)
+	mux.HandleFunc(
+		"DELETE /repos/owner/repo/releases/assets/2",
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Fatalf("expected the unchanged asset to be kept")
+		},
+	)
+
+	gh := newTestGitHubClient(t, mux)
+	release := &github.RepositoryRelease{ID: github.Int64(7)}
+	asset := File{Name: "metrics.svg", Content: []byte("<svg/>")}
+	replaced, err := replaceReleaseAsset(context.Background(), gh, "owner", "repo", release, asset)
+	if err != nil || replaced {
+		t.Fatalf("expected the asset to be left unchanged, got %t, %v", replaced, err)
+	}
+}
+
+func TestReleaseAssetMediaTypeFollowsExtension(t *testing.T) {
+	tests := map[string]string{
+		"metrics.svg": "image/svg+xml",
+		"metrics.png": "image/png",
+		"metrics":     "application/octet-stream",
+	}
+	for name, want := range tests {
+		if got := releaseAssetMediaType(name); got != want {
+			t.Fatalf("expected %s for %s, got %s", want, name, got)
+		}
+	}
+}
+
+func TestReplaceReleaseAssetListsEveryPageBeforeDeleting(t *testing.T) {
+	pages := 0
+	deleted := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /repos/owner/repo/releases/7/assets",
+		func(w http.ResponseWriter, r *http.Request) {
+			pages++
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[{"id":4,"name":"metrics.svg","size":1}]`))
+				return
+			}
+			w.Header().Set(
+				"Link",
+				`<http://`+r.Host+r.URL.Path+`?page=2&per_page=100>; rel="next"`,
+			)
+			_, _ = w.Write(
+				[]byte(`[{"id":1,"name":"other.svg"},{"id":2,"name":"metrics.svg","size":1}]`),
+			)
+		},
+	)
+	mux.HandleFunc(
+		"DELETE /repos/owner/repo/releases/assets/{id}",
+		func(w http.ResponseWriter, r *http.Request) {
+			if pages != 2 {
+				t.Errorf("expected every page to be listed before deleting, got %d", pages)
+			}
+			deleted = append(deleted, r.PathValue("id"))
+			w.WriteHeader(http.StatusNoContent)
+		},
+	)
+	mux.HandleFunc(
+		"POST /repos/owner/repo/releases/7/assets",
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":5,"name":"metrics.svg"}`))
+		},
+	)
+
+	gh := newTestGitHubClient(t, mux)
+	release := &github.RepositoryRelease{ID: github.Int64(7)}
+	asset := File{Name: "metrics.svg", Content: []byte("<svg/>")}
+	replaced, err := replaceReleaseAsset(context.Background(), gh, "owner", "repo", release, asset)
+	if err != nil || !replaced {
+		t.Fatalf("expected the asset to be replaced, got %t, %v", replaced, err)
+	}
+	if len(deleted) != 2 || deleted[0] != "2" || deleted[1] != "4" {
+		t.Fatalf("expected the assets of both pages to be deleted, got %v", deleted)
+	}
+}
@@ -0,0 +1,153 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// Sizes of the language charts
+const (
+	// languageCircleSize is the diameter of the donut and pie charts
+	languageCircleSize = 140.0
+	// languageDonutHole is the radius of the hole of the donut chart as a
+	// fraction of its radius
+	languageDonutHole = 0.6
+	// languageBarsHeight is the height of the vertical bars chart, including
+	// the percentages above and the names below the bars
+	languageBarsHeight = 150.0
+	// languageBarsMaxWidth is the maximum width of a vertical bar
+	languageBarsMaxWidth = 40.0
+)
+
+// colourLanguages returns a copy of the languages with a colour for every
+// language
+func (r *Renderer) colourLanguages(languages []metrics.LanguageStat) []metrics.LanguageStat {
+	coloured := make([]metrics.LanguageStat, len(languages))
+	for i, lang := range languages {
+		coloured[i] = lang
+		coloured[i].Color = r.languageColour(lang)
+	}
+	return coloured
+}
+
+// languageColour returns the colour of the language set in the profile, its
+// own colour, or a fallback colour for languages GitHub has no colour for
+func (r *Renderer) languageColour(lang metrics.LanguageStat) string {
+	return r.Profile.LanguageColour(lang.Name, lang.Color)
+}
+
+// languagesCircleLayout returns a donut or pie chart of the languages with a
+// legend next to it. The hole is the radius of the hole in the middle as a
+// fraction of the radius of the chart, 0 for a pie chart.
+func (r *Renderer) languagesCircleLayout(
+	languages []metrics.LanguageStat,
+	hole float64,
+) *Node {
+	chart := Leaf(languageCircleSize, func(box Box) []svg.Element {
+		radius := languageCircleSize / 2
+		cx, cy := box.X+radius, box.Y+radius
+		elements := []svg.Element{}
+		angle := 0.0
+		for _, lang := range languages {
+			if lang.Percentage <= 0 {
+				continue
+			}
+			end := angle + lang.Percentage/100*2*math.Pi
+			elements = append(elements, svg.Path().
+				D(svg.String(arcPath(cx, cy, radius, radius*hole, angle, end))).
+				Fill(svg.String(lang.Color)))
+			angle = end
+		}
+		return elements
+	}).WithWidth(languageCircleSize)
+
+	legend := Column(0)
+	for _, lang := range languages {
+		legend.Children = append(legend.Children, r.languageLabelLayout(lang))
+	}
+
+	return Row(20, chart, legend).WithPadding(Insets{Top: 15, Bottom: 4})
+}
+
+// arcPath returns the path of the slice of a ring around cx, cy between the
+// angles start and end, in radians clockwise from the top. An inner radius
+// of 0 makes a slice of a pie. Each arc is drawn in two halves, so slices of
+// more than half of the circle, up to the whole circle, need no special
+// case.
+func arcPath(cx, cy, outer, inner, start, end float64) string {
+	at := func(radius, angle float64) string {
+		return fmt.Sprintf("%.2f %.2f", cx+radius*math.Sin(angle), cy-radius*math.Cos(angle))
+	}
+	mid := (start + end) / 2
+	var d strings.Builder
+	fmt.Fprintf(&d, "M %s", at(outer, start))
+	fmt.Fprintf(&d, " A %.2f %.2f 0 0 1 %s", outer, outer, at(outer, mid))
+	fmt.Fprintf(&d, " A %.2f %.2f 0 0 1 %s", outer, outer, at(outer, end))
+	if inner > 0 {
+		fmt.Fprintf(&d, " L %s", at(inner, end))
+		fmt.Fprintf(&d, " A %.2f %.2f 0 0 0 %s", inner, inner, at(inner, mid))
+		fmt.Fprintf(&d, " A %.2f %.2f 0 0 0 %s", inner, inner, at(inner, start))
+	} else {
+		fmt.Fprintf(&d, " L %.2f %.2f", cx, cy)
+	}
+	d.WriteString(" Z")
+	return d.String()
+}
+
+// languagesBarsLayout returns a vertical bar per language, scaled to the
+// most used language, with its percentage above and its name below
+func (r *Renderer) languagesBarsLayout(languages []metrics.LanguageStat) *Node {
+	return Leaf(languageBarsHeight, func(box Box) []svg.Element {
+		const percentageHeight, nameHeight = 16.0, 20.0
+		if len(languages) == 0 {
+			return nil
+		}
+		largest := 0.0
+		for _, lang := range languages {
+			largest = max(largest, lang.Percentage)
+		}
+		slot := box.Width / float64(len(languages))
+		barWidth := min(slot*0.6, languageBarsMaxWidth)
+		bottom := box.Y + box.Height - nameHeight
+		plotHeight := box.Height - percentageHeight - nameHeight
+
+		elements := []svg.Element{}
+		for i, lang := range languages {
+			centre := box.X + slot*(float64(i)+0.5)
+			height := 0.0
+			if largest > 0 {
+				height = lang.Percentage / largest * plotHeight
+			}
+			elements = append(elements,
+				svg.Rect().
+					Fill(svg.String(lang.Color)).
+					Width(svg.Px(barWidth)).
+					Height(svg.Px(height)).
+					X(svg.Px(centre-barWidth/2)).
+					Y(svg.Px(bottom-height)),
+				textElement(
+					centre,
+					bottom-height-4,
+					fontText12,
+					r.Profile.TextSecondary,
+					fmt.Sprintf("%.1f%%", lang.Percentage),
+					slot,
+				).TextAnchor(svg.String("middle")),
+				textElement(
+					centre,
+					bottom+15,
+					fontText12,
+					r.Profile.TextPrimary,
+					lang.Name,
+					slot,
+				).TextAnchor(svg.String("middle")),
+			)
+		}
+		return elements
+	}).WithPadding(Insets{Top: 15, Bottom: 4})
+}
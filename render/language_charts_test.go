@@ -0,0 +1,123 @@
+package render
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+func TestLanguagesLayoutDrawsLanguageChart(t *testing.T) {
+	languages := []metrics.LanguageStat{
+		{Name: "Go", Color: "#00ADD8", Percentage: 75},
+		{Name: "Templ", Percentage: 25},
+	}
+	for _, tc := range []struct {
+		chart string
+		want  []string
+	}{
+		{LanguageChartDonut, []string{"<path", " A 42.00 42.00 0 0 0 ", ">Templ<"}},
+		{LanguageChartPie, []string{"<path", " L 90.00 115.00 Z", ">Templ<"}},
+		{LanguageChartBars, []string{`height="114px"`, ">75.0%<", ">Templ<"}},
+	} {
+		renderer := New(theme.GetColourProfile("default"))
+		renderer.Options = Options{LanguageChart: tc.chart}
+
+		layout := renderer.languagesLayout(languages)
+		layout.Arrange(20, 0, 500)
+		var buf bytes.Buffer
+		if _, err := svg.New().AppendChildren(layout.Elements()...).WriteTo(&buf); err != nil {
+			t.Fatalf("failed to write SVG: %v", err)
+		}
+
+		for _, want := range append(tc.want, `fill="`+renderer.Profile.FallbackLanguageColour("Templ")+`"`) {
+			if !strings.Contains(buf.String(), want) {
+				t.Fatalf(
+					"expected the %s chart to contain %q, got %s",
+					tc.chart,
+					want,
+					buf.String(),
+				)
+			}
+		}
+	}
+	if languages[1].Color != "" {
+		t.Fatalf("expected the fallback colour not to change the languages")
+	}
+}
+
+func TestArcPathDrawsWholeCircle(t *testing.T) {
+	got := arcPath(50, 50, 40, 0, 0, 2*math.Pi)
+
+	want := "M 50.00 10.00 A 40.00 40.00 0 0 1 50.00 90.00 A 40.00 40.00 0 0 1 50.00 10.00 L 50.00 50.00 Z"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestArcPathDrawsWholeDonut(t *testing.T) {
+	got := arcPath(50, 50, 40, 24, 0, 2*math.Pi)
+
+	want := "M 50.00 10.00 A 40.00 40.00 0 0 1 50.00 90.00 A 40.00 40.00 0 0 1 50.00 10.00" +
+		" L 50.00 26.00 A 24.00 24.00 0 0 0 50.00 74.00 A 24.00 24.00 0 0 0 50.00 26.00 Z"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestArcPathDrawsTinySlice(t *testing.T) {
+	got := arcPath(50, 50, 40, 0, 0, 0.001)
+
+	want := "M 50.00 10.00 A 40.00 40.00 0 0 1 50.02 10.00 A 40.00 40.00 0 0 1 50.04 10.00 L 50.00 50.00 Z"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestArcPathCutsDonutHole(t *testing.T) {
+	outer := "M 50.00 10.00 A 40.00 40.00 0 0 1 78.28 21.72 A 40.00 40.00 0 0 1 90.00 50.00"
+
+	pie := arcPath(50, 50, 40, 0, 0, math.Pi/2)
+	if want := outer + " L 50.00 50.00 Z"; pie != want {
+		t.Fatalf("expected the pie slice to close at the centre %q, got %q", want, pie)
+	}
+	donut := arcPath(50, 50, 40, 24, 0, math.Pi/2)
+	want := outer + " L 74.00 50.00 A 24.00 24.00 0 0 0 66.97 33.03 A 24.00 24.00 0 0 0 50.00 26.00 Z"
+	if donut != want {
+		t.Fatalf("expected the donut slice to go back along the hole %q, got %q", want, donut)
+	}
+}
+
+func TestLanguagesBarsLayoutScalesBarsToLargestLanguage(t *testing.T) {
+	renderer := New(theme.GetColourProfile("default"))
+	layout := renderer.languagesBarsLayout([]metrics.LanguageStat{
+		{Name: "Go", Color: "#00ADD8", Percentage: 60},
+		{Name: "Python", Color: "#3572A5", Percentage: 30},
+		{Name: "Shell", Color: "#89e051", Percentage: 15},
+	})
+	layout.Arrange(0, 0, 300)
+
+	heights := []string{}
+	for _, element := range layout.Elements() {
+		var buf bytes.Buffer
+		if _, err := svg.New().AppendChildren(element).WriteTo(&buf); err != nil {
+			t.Fatalf("failed to write SVG: %v", err)
+		}
+		if _, rest, ok := strings.Cut(buf.String(), `<rect fill="`); ok {
+			_, height, _ := strings.Cut(rest, `height="`)
+			heights = append(heights, height[:strings.Index(height, `"`)])
+		}
+	}
+
+	// The plot is the 150px leaf less 16px for the percentages and 20px for
+	// the names
+	want := []string{"114px", "57px", "28.5px"}
+	if strings.Join(heights, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected bar heights %v, got %v", want, heights)
+	}
+}
@@ -0,0 +1,234 @@
+package render
+
+import "github.com/twpayne/go-svg"
+
+// Flow is the direction a container places its children in
+type Flow int
+
+// Directions a container can place its children in
+const (
+	FlowColumn Flow = iota
+	FlowRow
+)
+
+// Insets are the space between the edges of a box and its content
+type Insets struct {
+	Top    float64
+	Right  float64
+	Bottom float64
+	Left   float64
+}
+
+// Node is a box in a layout tree: a container placing its children in a
+// column or a row, or a leaf drawing content. Arrange computes the position
+// and size of every box from the available width, so content never has to
+// know where it ends up on the card.
+type Node struct {
+	Flow     Flow
+	Children []*Node
+	Padding  Insets
+	// Gap is the space between the children of a container, and between the
+	// lines of a wrapping row
+	Gap float64
+	// Wrap moves children of a row that do not fit onto a new line
+	Wrap bool
+	// Centre moves each line of a wrapping row to the middle of the row
+	Centre bool
+	// Width is the width of the node in a row. Children of a row without a
+	// width share the width left over equally.
+	Width float64
+	// Height is the height of a leaf, or the minimum height of a container
+	Height float64
+	// Draw draws a leaf into its content box
+	Draw func(box Box) []svg.Element
+	// Box is the position and size of the node, set by Arrange
+	Box Box
+}
+
+// Column returns a container placing the children top to bottom. Nil
+// children are left out, so optional content does not leave a gap.
+func Column(gap float64, children ...*Node) *Node {
+	return &Node{Flow: FlowColumn, Gap: gap, Children: present(children)}
+}
+
+// Row returns a container placing the children left to right. Nil children
+// are left out.
+func Row(gap float64, children ...*Node) *Node {
+	return &Node{Flow: FlowRow, Gap: gap, Children: present(children)}
+}
+
+// WrapRow returns a row that moves children that do not fit onto a new line.
+// Children without a width take up a whole line.
+func WrapRow(gap float64, children ...*Node) *Node {
+	row := Row(gap, children...)
+	row.Wrap = true
+	return row
+}
+
+// Centred returns a wrapping row with each line in the middle of the row
+func Centred(gap float64, children ...*Node) *Node {
+	row := WrapRow(gap, children...)
+	row.Centre = true
+	return row
+}
+
+// Leaf returns a node of the given height drawn by draw
+func Leaf(height float64, draw func(box Box) []svg.Element) *Node {
+	return &Node{Height: height, Draw: draw}
+}
+
+// WithPadding sets the padding of the node and returns it
+func (n *Node) WithPadding(padding Insets) *Node {
+	n.Padding = padding
+	return n
+}
+
+// WithWidth sets the width of the node in a row and returns it
+func (n *Node) WithWidth(width float64) *Node {
+	n.Width = width
+	return n
+}
+
+// Arrange places the node at x, y with the given width and returns its
+// height
+func (n *Node) Arrange(x, y, width float64) float64 {
+	inner := Box{
+		X:     x + n.Padding.Left,
+		Y:     y + n.Padding.Top,
+		Width: width - n.Padding.Left - n.Padding.Right,
+	}
+	switch {
+	case n.Draw != nil:
+		inner.Height = n.Height
+	case n.Flow == FlowRow && n.Wrap:
+		inner.Height = n.arrangeWrappingRow(inner)
+	case n.Flow == FlowRow:
+		inner.Height = n.arrangeRow(inner)
+	default:
+		inner.Height = n.arrangeColumn(inner)
+	}
+	height := max(inner.Height+n.Padding.Top+n.Padding.Bottom, n.Height)
+	n.Box = Box{X: x, Y: y, Width: width, Height: height}
+	return height
+}
+
+func (n *Node) arrangeColumn(inner Box) float64 {
+	y := inner.Y
+	for i, child := range n.Children {
+		if i > 0 {
+			y += n.Gap
+		}
+		y += child.Arrange(inner.X, y, inner.Width)
+	}
+	return y - inner.Y
+}
+
+func (n *Node) arrangeRow(inner Box) float64 {
+	remaining := inner.Width - n.Gap*float64(max(len(n.Children)-1, 0))
+	flexible := 0
+	for _, child := range n.Children {
+		if child.Width > 0 {
+			remaining -= child.Width
+		} else {
+			flexible++
+		}
+	}
+	share := 0.0
+	if flexible > 0 {
+		share = max(remaining/float64(flexible), 0)
+	}
+
+	x := inner.X
+	height := 0.0
+	for _, child := range n.Children {
+		width := child.Width
+		if width <= 0 {
+			width = share
+		}
+		height = max(height, child.Arrange(x, inner.Y, width))
+		x += width + n.Gap
+	}
+	return height
+}
+
+func (n *Node) arrangeWrappingRow(inner Box) float64 {
+	// Break the children into lines first, so a line can be centred before
+	// its children are placed
+	type line struct {
+		children []*Node
+		widths   []float64
+		width    float64
+	}
+	lines := []line{}
+	current := line{}
+	for _, child := range n.Children {
+		width := child.Width
+		if width <= 0 || width > inner.Width {
+			width = inner.Width
+		}
+		if len(current.children) > 0 && current.width+n.Gap+width > inner.Width {
+			lines = append(lines, current)
+			current = line{}
+		}
+		if len(current.children) > 0 {
+			current.width += n.Gap
+		}
+		current.children = append(current.children, child)
+		current.widths = append(current.widths, width)
+		current.width += width
+	}
+	if len(current.children) > 0 {
+		lines = append(lines, current)
+	}
+
+	y := inner.Y
+	for i, line := range lines {
+		if i > 0 {
+			y += n.Gap
+		}
+		x := inner.X
+		if n.Centre {
+			x += (inner.Width - line.width) / 2
+		}
+		lineHeight := 0.0
+		for j, child := range line.children {
+			lineHeight = max(lineHeight, child.Arrange(x, y, line.widths[j]))
+			x += line.widths[j] + n.Gap
+		}
+		y += lineHeight
+	}
+	return y - inner.Y
+}
+
+// Elements draws the leaves of the arranged tree in order
+func (n *Node) Elements() []svg.Element {
+	if n.Draw != nil {
+		return n.Draw(n.content())
+	}
+	elements := []svg.Element{}
+	for _, child := range n.Children {
+		elements = append(elements, child.Elements()...)
+	}
+	return elements
+}
+
+// content returns the box inside the padding
+func (n *Node) content() Box {
+	return Box{
+		X:      n.Box.X + n.Padding.Left,
+		Y:      n.Box.Y + n.Padding.Top,
+		Width:  n.Box.Width - n.Padding.Left - n.Padding.Right,
+		Height: n.Box.Height - n.Padding.Top - n.Padding.Bottom,
+	}
+}
+
+// present returns the nodes that are not nil
+func present(nodes []*Node) []*Node {
+	children := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node != nil {
+			children = append(children, node)
+		}
+	}
+	return children
+}
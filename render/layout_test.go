@@ -0,0 +1,70 @@
+package render
+
+import "testing"
+
+func TestArrangeColumnStacksChildrenWithGapsAndPadding(t *testing.T) {
+	first := Leaf(10, nil)
+	second := Leaf(20, nil)
+	column := Column(
+		5,
+		first,
+		nil,
+		second,
+	).WithPadding(Insets{Top: 2, Left: 3, Right: 4, Bottom: 1})
+
+	height := column.Arrange(100, 50, 200)
+
+	if height != 2+10+5+20+1 {
+		t.Fatalf("expected the column to fit its children, got height %g", height)
+	}
+	if first.Box != (Box{X: 103, Y: 52, Width: 193, Height: 10}) {
+		t.Fatalf("unexpected first box %+v", first.Box)
+	}
+	if second.Box != (Box{X: 103, Y: 67, Width: 193, Height: 20}) {
+		t.Fatalf("expected the nil child not to leave a gap, got %+v", second.Box)
+	}
+}
+
+func TestArrangeRowSharesRemainingWidth(t *testing.T) {
+	fixed := Leaf(10, nil).WithWidth(100)
+	left := Leaf(30, nil)
+	right := Column(0, Leaf(15, nil)).WithPadding(Insets{Top: 5})
+	row := Row(10, fixed, left, right)
+
+	height := row.Arrange(0, 0, 320)
+
+	if height != 30 {
+		t.Fatalf("expected the row to be as tall as its tallest child, got %g", height)
+	}
+	for _, tc := range []struct {
+		node *Node
+		x    float64
+		w    float64
+	}{{fixed, 0, 100}, {left, 110, 100}, {right, 220, 100}} {
+		if tc.node.Box.X != tc.x || tc.node.Box.Width != tc.w {
+			t.Fatalf("expected box at %g with width %g, got %+v", tc.x, tc.w, tc.node.Box)
+		}
+	}
+	if right.Children[0].Box.Y != 5 {
+		t.Fatalf("expected padding to move the content, got %+v", right.Children[0].Box)
+	}
+}
+
+func TestArrangeCentredRowCentresEachLine(t *testing.T) {
+	first := Leaf(10, nil).WithWidth(60)
+	second := Leaf(10, nil).WithWidth(60)
+	third := Leaf(20, nil).WithWidth(40)
+	row := Centred(10, first, second, third)
+
+	height := row.Arrange(0, 0, 150)
+
+	if height != 10+10+20 {
+		t.Fatalf("expected two lines, got height %g", height)
+	}
+	if first.Box.X != 10 || second.Box.X != 80 {
+		t.Fatalf("expected the first line to be centred, got %+v and %+v", first.Box, second.Box)
+	}
+	if third.Box != (Box{X: 55, Y: 20, Width: 40, Height: 20}) {
+		t.Fatalf("expected the second line to be centred, got %+v", third.Box)
+	}
+}
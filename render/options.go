@@ -0,0 +1,219 @@
+package render
+
+import "slices"
+
+// Names of the built-in sections
+const (
+	SectionProfile   = "profile"
+	SectionStats     = "stats"
+	SectionLanguages = "languages"
+	SectionCalendar  = "calendar"
+	// SectionMonth is the contributions of the current month, also shown in
+	// the stats section
+	SectionMonth = "month"
+)
+
+// Contribution calendar styles accepted in Options.CalendarStyle
+const (
+	CalendarStyleIsometric = "isometric"
+	CalendarStyleGrid      = "grid"
+)
+
+// Language charts accepted in Options.LanguageChart
+const (
+	// LanguageChartBar is a single stacked bar with a label per language
+	LanguageChartBar = "bar"
+	// LanguageChartDonut is a ring of a slice per language with a legend
+	LanguageChartDonut = "donut"
+	// LanguageChartPie is a disc of a slice per language with a legend
+	LanguageChartPie = "pie"
+	// LanguageChartBars is a vertical bar per language
+	LanguageChartBars = "bars"
+)
+
+// Card sizes accepted in Options.Size
+const (
+	SizeCompact  = "compact"
+	SizeStandard = "standard"
+	SizeWide     = "wide"
+)
+
+// DefaultSections lists the sections rendered when no sections are configured,
+// in their default order.
+var DefaultSections = []string{
+	SectionProfile,
+	SectionStats,
+	SectionLanguages,
+	SectionCalendar,
+}
+
+// CalendarStyles lists the supported contribution calendar styles
+var CalendarStyles = []string{CalendarStyleIsometric, CalendarStyleGrid}
+
+// LanguageCharts lists the supported language charts
+var LanguageCharts = []string{
+	LanguageChartBar,
+	LanguageChartDonut,
+	LanguageChartPie,
+	LanguageChartBars,
+}
+
+// Sizes lists the supported card sizes
+var Sizes = []string{SizeCompact, SizeStandard, SizeWide}
+
+// DefaultOtherLanguagesAfter is the number of languages shown on their own
+// before the rest are grouped into Other
+const DefaultOtherLanguagesAfter = 10
+
+// sizePreset is the width of a card size and the defaults it re-flows the
+// sections with
+type sizePreset struct {
+	width     float64
+	sections  []string
+	statLines []string
+	// languageLimit is the default maximum number of languages, 0 for all
+	languageLimit int
+	// calendarScale is how far the isometric calendar may be scaled up to
+	// fill its width
+	calendarScale float64
+}
+
+// sizePresets holds the preset of every card size
+var sizePresets = map[string]sizePreset{
+	// A narrow vertical card for sidebars with the key stats
+	SizeCompact: {
+		width:         500,
+		sections:      []string{SectionProfile, SectionStats, SectionLanguages},
+		statLines:     []string{"commits", "pull_request_reviews", "pull_requests", "issues"},
+		languageLimit: 5,
+		calendarScale: 1.22,
+	},
+	SizeStandard: {
+		width:         1000,
+		sections:      DefaultSections,
+		calendarScale: 1.22,
+	},
+	// A card for dashboards with the profile next to the stats and a larger
+	// full-year calendar
+	SizeWide: {
+		width:         1400,
+		sections:      DefaultSections,
+		calendarScale: 2,
+	},
+}
+
+// Options configures which sections are rendered and how
+type Options struct {
+	// Size is the card size, standard by default. It sets the width of the
+	// card and the defaults of the other options.
+	Size string
+	// Sections are rendered in this order, flowing left to right and top to
+	// bottom. Nil renders the sections of the card size, DefaultSections for
+	// standard cards.
+	Sections []string
+	// LanguageLimit is the maximum number of languages shown, or 0 for the
+	// default of the card size
+	LanguageLimit int
+	// OtherLanguagesAfter is the number of languages shown on their own
+	// before the rest are grouped into Other, or 0 for
+	// DefaultOtherLanguagesAfter
+	OtherLanguagesAfter int
+	// LanguageChart is the chart of the languages section, a bar by default
+	LanguageChart string
+	// CalendarStyle is the style of the year contribution calendar, isometric
+	// by default
+	CalendarStyle string
+	// StatLines are the stat lines shown in the stats section, in order. Nil
+	// shows the lines of the card size, every line in StatLines for standard
+	// cards.
+	StatLines []string
+}
+
+// preset returns the preset of the card size
+func (o Options) preset() sizePreset {
+	if preset, exists := sizePresets[o.Size]; exists {
+		return preset
+	}
+	return sizePresets[SizeStandard]
+}
+
+// sections returns the sections to render
+func (o Options) sections() []string {
+	if o.Sections == nil {
+		return o.preset().sections
+	}
+	return o.Sections
+}
+
+// statLines returns the names of the stat lines to show
+func (o Options) statLines() []string {
+	if o.StatLines != nil {
+		return o.StatLines
+	}
+	if lines := o.preset().statLines; lines != nil {
+		return lines
+	}
+	return StatLines()
+}
+
+// languageLimit returns the maximum number of languages, 0 for all
+func (o Options) languageLimit() int {
+	if o.LanguageLimit > 0 {
+		return o.LanguageLimit
+	}
+	return o.preset().languageLimit
+}
+
+// otherLanguagesAfter returns the number of languages shown before the rest
+// are grouped into Other
+func (o Options) otherLanguagesAfter() int {
+	if o.OtherLanguagesAfter > 0 {
+		return o.OtherLanguagesAfter
+	}
+	return DefaultOtherLanguagesAfter
+}
+
+// languageChart returns the chart of the languages section
+func (o Options) languageChart() string {
+	if o.LanguageChart == "" {
+		return LanguageChartBar
+	}
+	return o.LanguageChart
+}
+
+// IsSize reports whether name is a supported card size
+func IsSize(name string) bool {
+	_, exists := sizePresets[name]
+	return exists
+}
+
+// IsLanguageChart reports whether name is a supported language chart
+func IsLanguageChart(name string) bool {
+	return slices.Contains(LanguageCharts, name)
+}
+
+// IsSection reports whether name is a registered section
+func IsSection(name string) bool {
+	_, exists := LookupSection(name)
+	return exists
+}
+
+// IsStatLine reports whether name is a known stat line
+func IsStatLine(name string) bool {
+	for _, line := range statLines {
+		if line.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// StatLines returns the names of the available stat lines in their default
+// order
+func StatLines() []string {
+	names := make([]string, 0, len(statLines))
+	for _, line := range statLines {
+		names = append(names, line.Name)
+	}
+	return names
+}
@@ -0,0 +1,209 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+func testDocument() *metrics.Metrics {
+	return &metrics.Metrics{
+		SchemaVersion: metrics.SchemaVersion,
+		GeneratedAt:   time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+		User:          &metrics.GitHubUserInfo{Login: "octocat", Name: "Octo Cat"},
+		Totals: &metrics.GitHubTotalsStats{
+			TotalCommits:      1200,
+			TotalIssues:       7,
+			TotalRepositories: 12,
+			TotalStargazers:   34,
+		},
+		Languages: []metrics.LanguageStat{
+			{Name: "Go", Color: "#00ADD8", Percentage: 60},
+			{Name: "Python", Color: "#3572A5", Percentage: 30},
+			{Name: "Shell", Color: "#89e051", Percentage: 10},
+		},
+		Calendar: &metrics.ContributionCalendar{
+			TotalContributions: 3,
+			Weeks: []metrics.ContributionWeek{{ContributionDays: []metrics.ContributionDay{
+				{Date: "2026-01-26", ContributionCount: 3, Color: theme.GitHubContribMediumLow},
+			}}},
+		},
+	}
+}
+
+func renderString(t *testing.T, renderer *Renderer) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := renderer.Render(testDocument()).WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write SVG: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRenderStacksSectionsInConfiguredOrder(t *testing.T) {
+	renderer := New(theme.GetColourProfile("default"))
+	renderer.Options = Options{Sections: []string{SectionCalendar, SectionProfile}}
+
+	got := renderString(t, renderer)
+
+	for _, want := range []string{
+		`viewBox="0 0 1000 243"`,
+		`transform="translate(20 10)"`,
+		`transform="translate(20 153)"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected SVG to contain %q, got %s", want, got)
+		}
+	}
+	if strings.Contains(got, "Activity") {
+		t.Fatalf("expected disabled stats section not to be rendered")
+	}
+}
+
+func TestRenderAppliesSectionOptions(t *testing.T) {
+	renderer := New(theme.GetColourProfile("default"))
+	renderer.Options = Options{LanguageLimit: 2, StatLines: []string{"issues"}}
+
+	got := renderString(t, renderer)
+
+	if strings.Contains(got, ">Shell<") || !strings.Contains(got, ">Python<") {
+		t.Fatalf("expected languages to be limited to the top 2")
+	}
+	if !strings.Contains(got, "❗ 7 Issues opened") || strings.Contains(got, "Commits<") {
+		t.Fatalf("expected only the issues stat line")
+	}
+	if strings.Contains(got, "Community stats") {
+		t.Fatalf("expected columns without lines to be omitted")
+	}
+}
+
+func TestRenderBuildsTitleAndStatsFromDocument(t *testing.T) {
+	renderer := New(theme.GetColourProfile("default"))
+	renderer.Options = Options{StatLines: []string{"stargazers"}}
+
+	got := renderString(t, renderer)
+
+	if !strings.Contains(got, "<title>Octo Cat - Coding Metrics</title>") {
+		t.Fatalf("expected the title to name the user")
+	}
+	if !strings.Contains(got, "📚 12 Repositories") || !strings.Contains(got, "⭐ 34 Stargazers") {
+		t.Fatalf("expected the repository stats of the document")
+	}
+}
+
+func TestRenderHidesUnavailableStatLines(t *testing.T) {
+	document := testDocument()
+	document.Totals.Unavailable = []string{"issues", "stargazers", "forkers", "watchers", "sponsors"}
+	renderer := New(theme.GetColourProfile("default"))
+	renderer.Options = Options{StatLines: []string{"commits", "issues", "stargazers"}}
+
+	var buf bytes.Buffer
+	if _, err := renderer.Render(document).WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write SVG: %v", err)
+	}
+	got := buf.String()
+
+	if !strings.Contains(got, "💻 1200 Commits") {
+		t.Fatalf("expected the available stat lines to be shown")
+	}
+	if strings.Contains(got, "Issues opened") || strings.Contains(got, "Repositories") {
+		t.Fatalf("expected the unavailable stat lines and their empty column to be hidden")
+	}
+}
+
+func TestRenderSizePresetsReflowSections(t *testing.T) {
+	renderer := New(theme.GetColourProfile("default"))
+
+	renderer.Options = Options{Size: SizeCompact}
+	compact := renderString(t, renderer)
+	if !strings.Contains(compact, `viewBox="0 0 500 `) {
+		t.Fatalf("expected a compact card to be 500px wide, got %s", compact)
+	}
+	if strings.Contains(compact, "Contributions calendar") ||
+		strings.Contains(compact, "Community stats") {
+		t.Fatalf("expected a compact card to show only the key stats, got %s", compact)
+	}
+
+	renderer.Options = Options{Size: SizeWide}
+	wide := renderString(t, renderer)
+	for _, want := range []string{`viewBox="0 0 1400 `, `transform="translate(420 10)"`} {
+		if !strings.Contains(wide, want) {
+			t.Fatalf("expected a wide card with the stats next to the profile, got %s", wide)
+		}
+	}
+}
+
+func TestLimitLanguagesRenormalizesPercentages(t *testing.T) {
+	languages := limitLanguages(testDocument().Languages, 2)
+
+	if len(languages) != 2 {
+		t.Fatalf("expected 2 languages, got %d", len(languages))
+	}
+	total := languages[0].Percentage + languages[1].Percentage
+	if total < 99.99 || total > 100.01 {
+		t.Fatalf("expected percentages to sum to 100, got %f", total)
+	}
+}
+
+func TestLanguagesLayoutWrapsLabelsAndGroupsOther(t *testing.T) {
+	languages := []metrics.LanguageStat{}
+	for _, name := range []string{
+		"Jupyter Notebook", "TypeScript", "JavaScript", "Python", "Dockerfile", "Go",
+		"Shell", "Makefile", "HTML", "CSS", "Rust", "C", "Vim Script", "Lua",
+	} {
+		languages = append(
+			languages,
+			metrics.LanguageStat{Name: name, Color: "#000000", Percentage: 5},
+		)
+	}
+	languages[0].Percentage = 35
+	renderer := New(theme.GetColourProfile("default"))
+
+	layout := renderer.languagesLayout(languages)
+	layout.Arrange(20, 0, 600)
+
+	labels := layout.Children[2].Children
+	if len(labels) != DefaultOtherLanguagesAfter+1 {
+		t.Fatalf("expected %d labels with Other, got %d", DefaultOtherLanguagesAfter+1, len(labels))
+	}
+	lines := map[float64]float64{}
+	for _, label := range labels {
+		if label.Box.X < 20 || label.Box.X+label.Box.Width > 620 {
+			t.Fatalf("expected the label to fit the section, got %+v", label.Box)
+		}
+		if end, exists := lines[label.Box.Y]; exists && label.Box.X < end {
+			t.Fatalf("expected labels on a line not to overlap, got %+v", label.Box)
+		}
+		lines[label.Box.Y] = label.Box.X + label.Box.Width
+	}
+	if len(lines) < 2 {
+		t.Fatalf("expected the labels to wrap, got %d lines", len(lines))
+	}
+
+	var buf bytes.Buffer
+	if _, err := svg.New().AppendChildren(layout.Elements()...).WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write SVG: %v", err)
+	}
+	for _, want := range []string{">Jupyter Notebook<", ">35.0%<", ">Other<", ">20.0%<"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected the labels to contain %q, got %s", want, buf.String())
+		}
+	}
+}
+
+func TestRenderAppliesOtherLanguagesAfter(t *testing.T) {
+	renderer := New(theme.GetColourProfile("default"))
+	renderer.Options = Options{OtherLanguagesAfter: 2}
+
+	got := renderString(t, renderer)
+
+	if !strings.Contains(got, ">Other<") || strings.Contains(got, ">Shell<") {
+		t.Fatalf("expected Shell to be grouped into Other, got %s", got)
+	}
+}
@@ -0,0 +1,206 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// Requirement is a part of the metrics document a section is rendered from
+type Requirement string
+
+// Parts of the metrics document sections can require
+const (
+	RequiresUser      Requirement = "user"
+	RequiresTotals    Requirement = "totals"
+	RequiresLanguages Requirement = "languages"
+	RequiresCalendar  Requirement = "calendar"
+)
+
+// SatisfiedBy reports whether the document has the required data. The
+// languages requirement needs at least one language.
+func (req Requirement) SatisfiedBy(document *metrics.Metrics) bool {
+	switch req {
+	case RequiresUser:
+		return document.User != nil
+	case RequiresTotals:
+		return document.Totals != nil
+	case RequiresLanguages:
+		return len(document.Languages) > 0
+	case RequiresCalendar:
+		return document.Calendar != nil
+	default:
+		return false
+	}
+}
+
+// Size is a width and height in pixels
+type Size struct {
+	Width  float64
+	Height float64
+}
+
+// Box is the position and size of a box in a layout
+type Box struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// Section is a part of the card. Sections are registered by name so they can
+// be enabled, disabled and reordered with Options.Sections.
+type Section interface {
+	// Name is the name the section is selected by
+	Name() string
+	// Requires lists the parts of the document the section is rendered from.
+	// Sections are skipped if the document does not have them.
+	Requires() []Requirement
+	// PreferredSize is the size the section takes up for the document when
+	// given the width. Sections with a height of 0 are skipped.
+	PreferredSize(r *Renderer, document *metrics.Metrics, width float64) Size
+	// Render renders the section with its top left corner at the origin. The
+	// card moves it into place.
+	Render(r *Renderer, document *metrics.Metrics, size Size) svg.Element
+}
+
+// sections holds the registered sections by name
+var sections = map[string]Section{}
+
+// RegisterSection makes a section available by its name. It panics if the
+// name is empty or already registered.
+func RegisterSection(section Section) {
+	name := section.Name()
+	if name == "" {
+		panic("render: section name is empty")
+	}
+	if _, exists := sections[name]; exists {
+		panic(fmt.Sprintf("render: section %q is already registered", name))
+	}
+	sections[name] = section
+}
+
+// LookupSection returns the registered section with the given name
+func LookupSection(name string) (Section, bool) {
+	section, exists := sections[name]
+	return section, exists
+}
+
+// SectionNames returns the names of the registered sections, sorted
+func SectionNames() []string {
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// satisfied reports whether the document has everything the section requires
+func satisfied(section Section, document *metrics.Metrics) bool {
+	for _, req := range section.Requires() {
+		if !req.SatisfiedBy(document) {
+			return false
+		}
+	}
+	return true
+}
+
+// builtinSection is a section whose content is arranged by the layout engine
+type builtinSection struct {
+	name     string
+	requires []Requirement
+	// layout returns the layout tree of the section for the available width,
+	// or nil if the document has nothing to show. Sections narrower than the
+	// width set the width of the root node.
+	layout func(r *Renderer, document *metrics.Metrics, width float64) *Node
+}
+
+func (s builtinSection) Name() string {
+	return s.name
+}
+
+func (s builtinSection) Requires() []Requirement {
+	return s.requires
+}
+
+func (s builtinSection) PreferredSize(
+	r *Renderer,
+	document *metrics.Metrics,
+	width float64,
+) Size {
+	node := s.layout(r, document, width)
+	if node == nil {
+		return Size{}
+	}
+	if node.Width > 0 {
+		width = min(node.Width, width)
+	}
+	return Size{Width: width, Height: node.Arrange(0, 0, width)}
+}
+
+func (s builtinSection) Render(r *Renderer, document *metrics.Metrics, size Size) svg.Element {
+	node := s.layout(r, document, size.Width)
+	if node == nil {
+		return svg.G()
+	}
+	node.Arrange(0, 0, size.Width)
+	return svg.G().AppendChildren(node.Elements()...)
+}
+
+// translate moves the element, leaving it unwrapped if it does not move
+func translate(element svg.Element, dx, dy float64) svg.Element {
+	if dx == 0 && dy == 0 {
+		return element
+	}
+	group := svg.G(element)
+	group.Attrs["transform"] = svg.String(fmt.Sprintf("translate(%g %g)", dx, dy))
+	return group
+}
+
+func init() {
+	RegisterSection(builtinSection{
+		name:     SectionProfile,
+		requires: []Requirement{RequiresUser},
+		layout: func(r *Renderer, document *metrics.Metrics, width float64) *Node {
+			return r.profileLayout(document.User, document.RenderTime())
+		},
+	})
+	RegisterSection(builtinSection{
+		name:     SectionStats,
+		requires: []Requirement{RequiresUser, RequiresTotals, RequiresCalendar},
+		layout: func(r *Renderer, document *metrics.Metrics, width float64) *Node {
+			return r.statsLayout(
+				document.User,
+				document.Totals,
+				document.Calendar,
+				document.RenderTime(),
+			)
+		},
+	})
+	RegisterSection(builtinSection{
+		name:     SectionLanguages,
+		requires: []Requirement{RequiresLanguages},
+		layout: func(r *Renderer, document *metrics.Metrics, width float64) *Node {
+			return r.languagesLayout(document.Languages)
+		},
+	})
+	RegisterSection(builtinSection{
+		name:     SectionCalendar,
+		requires: []Requirement{RequiresCalendar},
+		layout: func(r *Renderer, document *metrics.Metrics, width float64) *Node {
+			return r.yearContributionCalendarLayout(document.Calendar, width)
+		},
+	})
+	RegisterSection(builtinSection{
+		name:     SectionMonth,
+		requires: []Requirement{RequiresCalendar},
+		layout: func(r *Renderer, document *metrics.Metrics, width float64) *Node {
+			return r.contributionGraphLayout(document.Calendar, document.RenderTime()).
+				WithWidth(contributionGraphWidth)
+		},
+	})
+}
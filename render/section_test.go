@@ -0,0 +1,110 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+// footerSection is a section registered by the tests
+type footerSection struct{}
+
+func (footerSection) Name() string { return "test_footer" }
+
+func (footerSection) Requires() []Requirement { return []Requirement{RequiresUser} }
+
+func (footerSection) PreferredSize(_ *Renderer, _ *metrics.Metrics, width float64) Size {
+	return Size{Width: width, Height: 30}
+}
+
+func (footerSection) Render(r *Renderer, document *metrics.Metrics, size Size) svg.Element {
+	return svg.Text(svg.CharData("Footer for "+document.User.Login)).XY(0, 20, svg.Px)
+}
+
+func init() {
+	RegisterSection(footerSection{})
+}
+
+func TestRenderRegisteredSection(t *testing.T) {
+	if !IsSection("test_footer") {
+		t.Fatalf("expected the registered section to be known")
+	}
+	renderer := New(theme.GetColourProfile("default"))
+	renderer.Options = Options{Sections: []string{SectionProfile, "test_footer"}}
+
+	got := renderString(t, renderer)
+
+	for _, want := range []string{
+		`viewBox="0 0 1000 130"`,
+		`transform="translate(20 100)"`,
+		"Footer for octocat",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected SVG to contain %q, got %s", want, got)
+		}
+	}
+}
+
+func TestRenderSkipsSectionsWithoutData(t *testing.T) {
+	document := testDocument()
+	document.Languages = nil
+	renderer := New(theme.GetColourProfile("default"))
+	renderer.Options = Options{Sections: []string{SectionProfile, SectionLanguages}}
+
+	var buf strings.Builder
+	if _, err := renderer.Render(document).WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write SVG: %v", err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, `viewBox="0 0 1000 100"`) {
+		t.Fatalf("expected the languages section to be skipped, got %s", got)
+	}
+}
+
+func TestRegisterSectionRejectsDuplicateNames(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected registering a duplicate section to panic")
+		}
+	}()
+	RegisterSection(footerSection{})
+}
+
+func TestRenderSectionFitsViewBoxToSection(t *testing.T) {
+	renderer := New(theme.GetColourProfile("dark"))
+
+	element, err := renderer.RenderSection(testDocument(), SectionMonth)
+	if err != nil {
+		t.Fatalf("expected the month section to render, got %v", err)
+	}
+	var buf strings.Builder
+	if _, err := element.WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write SVG: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`viewBox="0 0 270 135"`,
+		`fill="` + renderer.Profile.Background + `"`,
+		"📚 Contributions",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected SVG to contain %q, got %s", want, got)
+		}
+	}
+	if strings.Contains(got, "Activity") {
+		t.Fatalf("expected only the month section to be rendered")
+	}
+
+	document := testDocument()
+	document.Languages = nil
+	if _, err := renderer.RenderSection(document, SectionLanguages); err == nil {
+		t.Fatalf("expected an error for a section without data")
+	}
+	if _, err := renderer.RenderSection(document, "langs"); err == nil {
+		t.Fatalf("expected an error for an unknown section")
+	}
+}
@@ -0,0 +1,95 @@
+// Package render renders a metrics document as an SVG card.
+package render
+
+import (
+	"fmt"
+
+	svg "github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+// Renderer renders metrics documents with a colour profile
+type Renderer struct {
+	Profile theme.ColourProfile
+	Options Options
+}
+
+// New creates a Renderer using the given colour profile
+func New(profile theme.ColourProfile) *Renderer {
+	return &Renderer{Profile: profile}
+}
+
+// Render renders the metrics document as an SVG card
+func (r *Renderer) Render(document *metrics.Metrics) *svg.SVGElement {
+	svgWidth := r.Options.preset().width
+	children, svgHeight := r.generateSVGContent(document, svgWidth)
+	return r.createSVG(children, svgWidth, svgHeight)
+}
+
+// sectionPadding is the space around a section rendered on its own
+var sectionPadding = Insets{Top: 10, Right: 20, Bottom: 10, Left: 20}
+
+// RenderSection renders the named section as a standalone SVG with a viewBox
+// fitting the section. It returns an error if the section is unknown or the
+// document does not have the data it requires.
+func (r *Renderer) RenderSection(
+	document *metrics.Metrics,
+	name string,
+) (*svg.SVGElement, error) {
+	section, exists := LookupSection(name)
+	if !exists {
+		return nil, fmt.Errorf("unknown section %q", name)
+	}
+	if !satisfied(section, document) {
+		return nil, fmt.Errorf("the metrics document has no data for the %s section", name)
+	}
+	available := r.Options.preset().width - sectionPadding.Left - sectionPadding.Right
+	size := section.PreferredSize(r, document, available)
+	if size.Height <= 0 {
+		return nil, fmt.Errorf("the %s section has nothing to show", name)
+	}
+	size.Width = min(size.Width, available)
+
+	children := []svg.Element{
+		svg.Title(svg.CharData(cardTitle(document))),
+		svg.Desc(svg.CharData(Description)),
+		translate(
+			section.Render(r, document, size),
+			sectionPadding.Left,
+			sectionPadding.Top,
+		),
+	}
+	return r.createSVG(
+		children,
+		size.Width+sectionPadding.Left+sectionPadding.Right,
+		size.Height+sectionPadding.Top+sectionPadding.Bottom,
+	), nil
+}
+
+func (r *Renderer) createSVG(
+	svgChildren []svg.Element,
+	svgWidth, svgHeight float64,
+) *svg.SVGElement {
+	// Add a background rectangle with the profile's background color as the first element
+	bgRect := svg.Rect().
+		Fill(svg.String(r.Profile.Background)).
+		Width(svg.Px(svgWidth)).
+		Height(svg.Px(svgHeight)).
+		X(svg.Px(0)).
+		Y(svg.Px(0))
+
+	// Prepend the background to the children
+	allChildren := append([]svg.Element{bgRect}, svgChildren...)
+
+	root := svg.New().WidthHeight(svgWidth, svgHeight, svg.Px).ViewBox(0, 0, svgWidth, svgHeight)
+	if root.Attrs == nil {
+		root.Attrs = map[string]svg.AttrValue{}
+	}
+	root.Attrs["xmlns:xlink"] = svg.String("http://www.w3.org/1999/xlink")
+
+	return root.AppendChildren(
+		allChildren...,
+	)
+}
@@ -0,0 +1,1125 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/twpayne/go-svg"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+// Description is the description embedded in the SVG, also used as the
+// alternative text of the card
+var Description = "GitHub profile statistics visualization"
+
+// cardTitle returns the title embedded in the SVG, naming the user of the
+// document
+func cardTitle(document *metrics.Metrics) string {
+	if document.User == nil {
+		return "Coding Metrics"
+	}
+	name := document.User.Name
+	if name == "" {
+		name = document.User.Login
+	}
+	return name + " - Coding Metrics"
+}
+
+// Common font styles
+const (
+	fontStyle12px         = "font-family: -apple-system, BlinkMacSystemFont, Segoe UI; font-size: 12px;"
+	fontStyleSubtitle12px = "font-family: -apple-system, BlinkMacSystemFont, Segoe UI; font-size: 12px; font-weight: 600;"
+	fontStyle13px         = "font-family: -apple-system, BlinkMacSystemFont, Segoe UI; font-size: 13px;"
+	fontStyleHeader15px   = "font-family: -apple-system, BlinkMacSystemFont, Segoe UI; font-size: 15px; font-weight: 600;"
+	fontStyleName18px     = "font-family: -apple-system, BlinkMacSystemFont, Segoe UI; font-size: 18px; font-weight: 600;"
+)
+
+type contributionCalendarStats struct {
+	CurrentStreakDays int
+	BestStreakDays    int
+	HighestInDay      int
+	AveragePerDay     float64
+}
+
+type isometricLayout struct {
+	OriginX     float64
+	OriginY     float64
+	TileW       float64
+	TileH       float64
+	HeightStep  float64
+	MaxHeight   float64
+	StrokeWidth float64
+}
+
+// cardPadding is the space between the edges of the card and its sections
+var cardPadding = Insets{Top: 10, Left: 20, Right: 20}
+
+// Generate the main SVG content from the metrics document, returning the
+// elements and the height they take up. The configured sections flow left to
+// right and top to bottom, so sections narrower than the card can share a
+// line. Sections whose data the document does not have are skipped.
+func (r *Renderer) generateSVGContent(
+	document *metrics.Metrics,
+	cardWidth float64,
+) ([]svg.Element, float64) {
+	elements := []svg.Element{
+		svg.Title(svg.CharData(cardTitle(document))),
+		svg.Desc(svg.CharData(Description)),
+	}
+
+	card := WrapRow(0).WithPadding(cardPadding)
+	width := cardWidth - card.Padding.Left - card.Padding.Right
+	for _, name := range r.Options.sections() {
+		section, exists := LookupSection(name)
+		if !exists || !satisfied(section, document) {
+			continue
+		}
+		size := section.PreferredSize(r, document, width)
+		if size.Height <= 0 {
+			continue
+		}
+		leaf := Leaf(size.Height, func(box Box) []svg.Element {
+			content := section.Render(r, document, Size{Width: box.Width, Height: box.Height})
+			return []svg.Element{translate(content, box.X, box.Y)}
+		})
+		card.Children = append(card.Children, leaf.WithWidth(min(size.Width, width)))
+	}
+	height := card.Arrange(0, 0, cardWidth)
+
+	return append(elements, card.Elements()...), height
+}
+
+// textLeaf returns a leaf drawing a line of text with its baseline the given
+// distance below the top of the leaf. Text wider than the leaf is truncated.
+func textLeaf(height, baseline float64, font textFont, fill, text string) *Node {
+	return Leaf(height, func(box Box) []svg.Element {
+		return []svg.Element{textElement(box.X, box.Y+baseline, font, fill, text, box.Width)}
+	})
+}
+
+// textElement returns the text at x, y truncated to the width
+func textElement(x, y float64, font textFont, fill, text string, width float64) *svg.TextElement {
+	return svg.Text(svg.CharData(font.Truncate(text, width))).
+		XY(x, y, svg.Px).
+		Fill(svg.String(fill)).
+		Style(svg.String(font.Style))
+}
+
+// headerLeaf returns a leaf with a section header
+func (r *Renderer) headerLeaf(header string) *Node {
+	return textLeaf(20, 15, fontHeader15, r.Profile.AccentPrimary, header)
+}
+
+// calendarNotesWidth is the width of the notes panel next to the year
+// calendar
+const calendarNotesWidth = 280.0
+
+// yearContributionCalendarLayout returns the year calendar section: the graph
+// with the notes panel to its right, or below it if the card is too narrow
+func (r *Renderer) yearContributionCalendarLayout(
+	contributionCalendar *metrics.ContributionCalendar,
+	width float64,
+) *Node {
+	if len(contributionCalendar.Weeks) == 0 {
+		return nil
+	}
+
+	title := textLeaf(25, 20, fontHeader15, r.Profile.AccentPrimary, "🗓️ Contributions calendar")
+	const gap = 40.0
+	graphWidth := width - gap - calendarNotesWidth
+	if graphWidth < calendarNotesWidth {
+		graphWidth = width
+	}
+	graph := r.isometricCalendarLayout(contributionCalendar.Weeks, graphWidth)
+	if r.Options.CalendarStyle == CalendarStyleGrid {
+		graph = r.gridCalendarLayout(contributionCalendar.Weeks, graphWidth)
+	}
+	notes := r.contributionCalendarNotesLayout(
+		calculateContributionCalendarStats(contributionCalendar),
+	)
+
+	return Column(
+		0,
+		title,
+		WrapRow(gap, graph.WithWidth(graphWidth), notes.WithWidth(calendarNotesWidth)),
+	)
+}
+
+// isometricCalendarLayout returns a leaf drawing the year calendar as
+// isometric cubes, tall enough for the highest cube
+func (r *Renderer) isometricCalendarLayout(
+	weeks []metrics.ContributionWeek,
+	graphWidth float64,
+) *Node {
+	const graphRightPadding = 15.0
+
+	rows := 7
+	cols := len(weeks)
+
+	tileW, tileH, heightStep, maxHeight, _ := calculateIsometricSizing(
+		cols,
+		rows,
+		graphWidth,
+		r.Options.preset().calendarScale,
+	)
+	// The highest point is the roof of the top left cube and the lowest the
+	// base of the bottom right tile
+	aboveOrigin := float64(rows-1)*tileH/2.0 + maxHeight
+	height := math.Ceil(aboveOrigin + float64(cols-1)*tileH/2.0 + tileH)
+
+	return Leaf(height, func(box Box) []svg.Element {
+		// Align the isometric grid toward the notes panel to reduce empty right-side space.
+		graphRight := box.X + box.Width - graphRightPadding
+		originX := graphRight - (float64(cols+rows-1) * tileW / 2.0)
+		if originX < box.X+tileW/2.0 {
+			originX = box.X + tileW/2.0
+		}
+		layout := isometricLayout{
+			OriginX:     originX,
+			OriginY:     box.Y + aboveOrigin,
+			TileW:       tileW,
+			TileH:       tileH,
+			HeightStep:  heightStep,
+			MaxHeight:   maxHeight,
+			StrokeWidth: 0.6,
+		}
+
+		elements := r.generateIsometricBaseTiles(
+			weeks,
+			cols,
+			rows,
+			layout.OriginX,
+			layout.OriginY,
+			layout.TileW,
+			layout.TileH,
+		)
+		return append(elements, r.generateIsometricExtrusions(weeks, cols, rows, layout)...)
+	})
+}
+
+// gridCalendarLayout returns a leaf drawing the year calendar as a flat grid
+// of squares with a column per week, like the calendar on GitHub profiles.
+func (r *Renderer) gridCalendarLayout(
+	weeks []metrics.ContributionWeek,
+	graphWidth float64,
+) *Node {
+	const (
+		maxSquareSize = 11.0
+		squareGap     = 2.0
+	)
+
+	squareSize := min(graphWidth/float64(len(weeks))-squareGap, maxSquareSize)
+	height := 7*(squareSize+squareGap) - squareGap
+
+	leaf := Leaf(height, func(box Box) []svg.Element {
+		squares := []svg.Element{}
+		for col, week := range weeks {
+			for _, day := range week.ContributionDays {
+				dayDate, err := time.Parse("2006-01-02", day.Date)
+				if err != nil {
+					continue
+				}
+				row := int(dayDate.Weekday())
+				squares = append(squares, svg.Rect().
+					Fill(svg.String(r.Profile.GetContributionColour(day.Color))).
+					Width(svg.Px(squareSize)).
+					Height(svg.Px(squareSize)).
+					X(svg.Px(box.X+float64(col)*(squareSize+squareGap))).
+					Y(svg.Px(box.Y+float64(row)*(squareSize+squareGap))).
+					RX(svg.Px(2)))
+			}
+		}
+		return squares
+	})
+	return leaf.WithPadding(Insets{Top: 10})
+}
+
+type point struct {
+	X float64
+	Y float64
+}
+
+func diamondPoints(x, y, tileW, tileH float64) []point {
+	hw := tileW / 2.0
+	hh := tileH / 2.0
+	return []point{
+		{X: x, Y: y},
+		{X: x + hw, Y: y + hh},
+		{X: x, Y: y + tileH},
+		{X: x - hw, Y: y + hh},
+	}
+}
+
+func toSVGPoints(points []point) svg.Points {
+	out := make(svg.Points, 0, len(points))
+	for _, p := range points {
+		out = append(out, []float64{p.X, p.Y})
+	}
+	return out
+}
+
+func calculateContributionCalendarStats(
+	contributionCalendar *metrics.ContributionCalendar,
+) contributionCalendarStats {
+	if contributionCalendar == nil {
+		return contributionCalendarStats{}
+	}
+
+	dates, counts, maxInDay := collectContributionCounts(contributionCalendar)
+	if len(dates) == 0 {
+		return contributionCalendarStats{}
+	}
+
+	currentStreak := calculateCurrentStreak(dates, counts)
+	bestStreak := calculateBestStreak(dates, counts)
+	avg := calculateAveragePerDay(contributionCalendar.TotalContributions, len(dates))
+
+	return contributionCalendarStats{
+		CurrentStreakDays: currentStreak,
+		BestStreakDays:    bestStreak,
+		HighestInDay:      maxInDay,
+		AveragePerDay:     avg,
+	}
+}
+
+func calculateIsometricSizing(
+	cols, rows int,
+	graphMaxWidth, maxScaleUp float64,
+) (tileW, tileH, heightStep, maxHeight, gridWidth float64) {
+	// A slightly shallower angle than the classic 2:1 isometric projection.
+	// (Lower tileH relative to tileW => shallower projection.)
+	tileW = 12.0
+	tileH = 4.8
+	heightStep = 4.0
+
+	maxHeight = 4.0 * heightStep
+	gridWidth = ((float64(cols+rows-2) * tileW) / 2.0) + tileW
+
+	// If we have extra horizontal room, scale up a bit (capped) so the calendar
+	// fills more of the empty space before the notes panel.
+	if gridWidth < graphMaxWidth {
+		scaleUp := graphMaxWidth / gridWidth
+		if scaleUp > maxScaleUp {
+			scaleUp = maxScaleUp
+		}
+		if scaleUp > 1.0 {
+			tileW *= scaleUp
+			tileH *= scaleUp
+			heightStep *= scaleUp
+			maxHeight = 4.0 * heightStep
+			gridWidth = ((float64(cols+rows-2) * tileW) / 2.0) + tileW
+		}
+		return tileW, tileH, heightStep, maxHeight, gridWidth
+	}
+
+	// Otherwise scale down to fit.
+	scaleDown := graphMaxWidth / gridWidth
+	tileW *= scaleDown
+	tileH *= scaleDown
+	heightStep *= scaleDown
+	maxHeight = 4.0 * heightStep
+	gridWidth = ((float64(cols+rows-2) * tileW) / 2.0) + tileW
+	return tileW, tileH, heightStep, maxHeight, gridWidth
+}
+
+func (r *Renderer) generateIsometricBaseTiles(
+	weeks []metrics.ContributionWeek,
+	cols, rows int,
+	originX, originY, tileW, tileH float64,
+) []svg.Element {
+	elements := make([]svg.Element, 0)
+	for s := 0; s <= (cols-1)+(rows-1); s++ {
+		for row := rows - 1; row >= 0; row-- {
+			col := s - row
+			if col < 0 || col >= cols {
+				continue
+			}
+			if row >= len(weeks[col].ContributionDays) {
+				continue
+			}
+			day := weeks[col].ContributionDays[row]
+			baseColour := r.Profile.ContributionLevel0
+			if day.Color != "" {
+				baseColour = r.Profile.GetContributionColour(day.Color)
+			}
+			x := originX + (float64(col+row) * tileW / 2.0)
+			y := originY + (float64(col) * tileH / 2.0) - (float64(row) * tileH / 2.0)
+			baseDiamond := diamondPoints(x, y, tileW, tileH)
+			elements = append(elements,
+				svg.Polygon().
+					Points(toSVGPoints(baseDiamond)).
+					Fill(svg.String(baseColour)).
+					Stroke(svg.String(r.Profile.Background)).
+					StrokeWidth(svg.Px(0.6)),
+			)
+		}
+	}
+	return elements
+}
+
+func (r *Renderer) generateIsometricExtrusions(
+	weeks []metrics.ContributionWeek,
+	cols, rows int,
+	layout isometricLayout,
+) []svg.Element {
+	type cubePos struct {
+		Col   int
+		Row   int
+		BaseX float64
+		BaseY float64
+		Day   metrics.ContributionDay
+	}
+
+	// Collect cubes and sort back-to-front by their screen-space base position.
+	// This keeps roofs visible and avoids later tiles overpainting earlier tops.
+	cubes := make([]cubePos, 0)
+	for col := 0; col < cols; col++ {
+		if col >= len(weeks) {
+			break
+		}
+		for row := 0; row < rows; row++ {
+			if row >= len(weeks[col].ContributionDays) {
+				continue
+			}
+			day := weeks[col].ContributionDays[row]
+			if theme.ContributionLevel(day.Color) <= 0 {
+				continue
+			}
+			x := layout.OriginX + (float64(col+row) * layout.TileW / 2.0)
+			y := layout.OriginY + (float64(col) * layout.TileH / 2.0) - (float64(row) * layout.TileH / 2.0)
+			cubes = append(
+				cubes,
+				cubePos{Col: col, Row: row, BaseX: x, BaseY: y + layout.TileH, Day: day},
+			)
+		}
+	}
+	sort.Slice(cubes, func(i, j int) bool {
+		if cubes[i].BaseY == cubes[j].BaseY {
+			return cubes[i].BaseX < cubes[j].BaseX
+		}
+		return cubes[i].BaseY < cubes[j].BaseY
+	})
+
+	elements := make([]svg.Element, 0, len(cubes)*6)
+	for _, c := range cubes {
+		r.maybeAppendExtrusion(&elements, weeks, cols, rows, c.Day, c.Col, c.Row, layout)
+	}
+	return elements
+}
+
+func contributionLevelAt(weeks []metrics.ContributionWeek, col, row int) int {
+	if col < 0 || col >= len(weeks) {
+		return 0
+	}
+	if row < 0 || row >= len(weeks[col].ContributionDays) {
+		return 0
+	}
+	return theme.ContributionLevel(weeks[col].ContributionDays[row].Color)
+}
+
+func (r *Renderer) maybeAppendExtrusion(
+	elements *[]svg.Element,
+	weeks []metrics.ContributionWeek,
+	cols, rows int,
+	day metrics.ContributionDay,
+	col, row int,
+	layout isometricLayout,
+) {
+	level := theme.ContributionLevel(day.Color)
+	if level <= 0 {
+		return
+	}
+
+	// Occlusion (requested): blocks to the right.
+	// If the neighbour "to the right" is equal-or-higher, hide the right face so
+	// equal-level runs read as flat.
+	drawRightFace := true
+	if col+1 < cols {
+		if contributionLevelAt(weeks, col+1, row) >= level {
+			drawRightFace = false
+		}
+	}
+
+	height := float64(level) * layout.HeightStep
+	if height > layout.MaxHeight {
+		height = layout.MaxHeight
+	}
+
+	baseColour := r.Profile.GetContributionColour(day.Color)
+	// Single-colour cubes (no per-face shading)
+	leftColour := baseColour
+	rightColour := baseColour
+	topColour := baseColour
+
+	x := layout.OriginX + (float64(col+row) * layout.TileW / 2.0)
+	y := layout.OriginY + (float64(col) * layout.TileH / 2.0) - (float64(row) * layout.TileH / 2.0)
+
+	base := diamondPoints(x, y, layout.TileW, layout.TileH)
+	top := diamondPoints(x, y-height, layout.TileW, layout.TileH)
+
+	leftFace := []point{top[3], top[2], base[2], base[3]}
+	rightFace := []point{top[1], top[2], base[2], base[1]}
+
+	*elements = append(*elements,
+		svg.Polygon().
+			Points(toSVGPoints(leftFace)).
+			Fill(svg.String(leftColour)),
+	)
+	if drawRightFace {
+		*elements = append(*elements,
+			svg.Polygon().
+				Points(toSVGPoints(rightFace)).
+				Fill(svg.String(rightColour)),
+		)
+	}
+	*elements = append(*elements,
+		svg.Polygon().
+			Points(toSVGPoints(top)).
+			Fill(svg.String(topColour)),
+	)
+
+	// Explicit outline (avoids tops reading like triangles)
+	stroke := svg.String(r.Profile.Background)
+	sw := svg.Px(layout.StrokeWidth)
+
+	// Top diamond outline (always)
+	*elements = append(*elements,
+		svg.Line().X1Y1X2Y2(top[0].X, top[0].Y, top[1].X, top[1].Y).Stroke(stroke).StrokeWidth(sw),
+		svg.Line().X1Y1X2Y2(top[1].X, top[1].Y, top[2].X, top[2].Y).Stroke(stroke).StrokeWidth(sw),
+		svg.Line().X1Y1X2Y2(top[2].X, top[2].Y, top[3].X, top[3].Y).Stroke(stroke).StrokeWidth(sw),
+		svg.Line().X1Y1X2Y2(top[3].X, top[3].Y, top[0].X, top[0].Y).Stroke(stroke).StrokeWidth(sw),
+	)
+
+	// Left/front outline edges (always visible in this projection)
+	*elements = append(
+		*elements,
+		svg.Line().
+			X1Y1X2Y2(top[3].X, top[3].Y, base[3].X, base[3].Y).
+			Stroke(stroke).
+			StrokeWidth(sw),
+		svg.Line().
+			X1Y1X2Y2(top[2].X, top[2].Y, base[2].X, base[2].Y).
+			Stroke(stroke).
+			StrokeWidth(sw),
+		svg.Line().
+			X1Y1X2Y2(base[3].X, base[3].Y, base[2].X, base[2].Y).
+			Stroke(stroke).
+			StrokeWidth(sw),
+	)
+
+	// Right outline edges only if the right face is exposed (blocked-to-the-right rule)
+	if drawRightFace {
+		*elements = append(
+			*elements,
+			svg.Line().
+				X1Y1X2Y2(top[1].X, top[1].Y, base[1].X, base[1].Y).
+				Stroke(stroke).
+				StrokeWidth(sw),
+			svg.Line().
+				X1Y1X2Y2(base[1].X, base[1].Y, base[2].X, base[2].Y).
+				Stroke(stroke).
+				StrokeWidth(sw),
+		)
+	}
+}
+
+// contributionCalendarNotesLayout returns a leaf with the streak and per day
+// notes shown next to the year calendar
+func (r *Renderer) contributionCalendarNotesLayout(stats contributionCalendarStats) *Node {
+	const (
+		firstBaseline = 5.0
+		lineGap       = 18.0
+	)
+	type note struct {
+		line   float64
+		text   string
+		header bool
+	}
+	notes := []note{
+		{line: 0, text: "📌 Commits streaks", header: true},
+		{line: 1, text: fmt.Sprintf("🔥 Current streak %d days", stats.CurrentStreakDays)},
+		{line: 2, text: fmt.Sprintf("✨ Best streak %d days", stats.BestStreakDays)},
+		{line: 4, text: "📈 Commits per day", header: true},
+		{line: 5, text: fmt.Sprintf("🏆 Highest in a day %d", stats.HighestInDay)},
+		{line: 6, text: fmt.Sprintf("📊 Average per day ~%.2f", stats.AveragePerDay)},
+	}
+
+	return Leaf(firstBaseline+lineGap*6+5, func(box Box) []svg.Element {
+		elements := make([]svg.Element, 0, len(notes))
+		for _, n := range notes {
+			fill, font := r.Profile.TextPrimary, fontText13
+			if n.header {
+				fill, font = r.Profile.AccentPrimary, fontHeader15
+			}
+			y := box.Y + firstBaseline + lineGap*n.line
+			elements = append(elements, textElement(box.X, y, font, fill, n.text, box.Width))
+		}
+		return elements
+	})
+}
+
+func collectContributionCounts(
+	contributionCalendar *metrics.ContributionCalendar,
+) ([]time.Time, map[time.Time]int, int) {
+	counts := map[time.Time]int{}
+	dates := make([]time.Time, 0)
+	maxInDay := 0
+	for _, week := range contributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			dayDate, err := time.Parse("2006-01-02", day.Date)
+			if err != nil {
+				continue
+			}
+			dayDate = time.Date(
+				dayDate.Year(),
+				dayDate.Month(),
+				dayDate.Day(),
+				0,
+				0,
+				0,
+				0,
+				time.UTC,
+			)
+			if _, exists := counts[dayDate]; !exists {
+				dates = append(dates, dayDate)
+			}
+			counts[dayDate] = day.ContributionCount
+			if day.ContributionCount > maxInDay {
+				maxInDay = day.ContributionCount
+			}
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, counts, maxInDay
+}
+
+func calculateCurrentStreak(dates []time.Time, counts map[time.Time]int) int {
+	if len(dates) == 0 {
+		return 0
+	}
+	streak := 0
+	for d := dates[len(dates)-1]; ; d = d.AddDate(0, 0, -1) {
+		count, ok := counts[d]
+		if !ok || count <= 0 {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+func calculateBestStreak(dates []time.Time, counts map[time.Time]int) int {
+	if len(dates) == 0 {
+		return 0
+	}
+	best := 0
+	running := 0
+	prev := dates[0]
+	for i, d := range dates {
+		if i > 0 {
+			expected := prev.AddDate(0, 0, 1)
+			if !d.Equal(expected) {
+				running = 0
+			}
+		}
+		if counts[d] > 0 {
+			running++
+			if running > best {
+				best = running
+			}
+		} else {
+			running = 0
+		}
+		prev = d
+	}
+	return best
+}
+
+func calculateAveragePerDay(total, days int) float64 {
+	if days <= 0 {
+		return 0
+	}
+	return float64(total) / float64(days)
+}
+
+// profileWidth is the width of the profile section, so it can share a line
+// with the stats on wide cards
+const profileWidth = 400.0
+
+// profileLayout returns the profile section: the avatar and name, followed
+// by when the user joined and their followers
+func (r *Renderer) profileLayout(userInfo *metrics.GitHubUserInfo, now time.Time) *Node {
+	yearsAgo := now.Sub(userInfo.JoinedGitHub).Hours() / 24 / 365
+
+	header := Leaf(24, func(box Box) []svg.Element {
+		// Embed the avatar so it renders when the SVG is displayed as an image.
+		avatarURL := userInfo.AvatarHref()
+		avatarImage := svg.Image().
+			Href(svg.String(avatarURL)).
+			Width(svg.Px(24)).Height(svg.Px(24)).
+			X(svg.Px(box.X)).Y(svg.Px(box.Y)).
+			Class(svg.String("avatar"))
+		if avatarImage.Attrs == nil {
+			avatarImage.Attrs = map[string]svg.AttrValue{}
+		}
+		avatarImage.Attrs["xlink:href"] = svg.String(avatarURL)
+
+		return []svg.Element{
+			// Use both href variants so SVG consumers with old xlink handling still work.
+			avatarImage,
+
+			// Name - positioned next to avatar
+			textElement(
+				box.X+30,
+				box.Y+17,
+				fontName18,
+				r.Profile.TextPrimary,
+				userInfo.Name,
+				box.Width-30,
+			),
+		}
+	})
+
+	line := func(text string) *Node {
+		return textLeaf(18, 13, fontText13, r.Profile.TextSecondary, text)
+	}
+
+	return Column(
+		5,
+		header,
+		Column(
+			0,
+			line(fmt.Sprintf("⏰ Joined GitHub %.0f years ago", yearsAgo)),
+			line(fmt.Sprintf("👥 Followed by %d users", userInfo.Followers)),
+		),
+	).WithPadding(Insets{Top: 18, Bottom: 7}).WithWidth(profileWidth)
+}
+
+// statLine is a line of the stats section, drawn in one of its columns
+type statLine struct {
+	Name   string
+	Column int
+	Text   func(userInfo *metrics.GitHubUserInfo, totals *metrics.GitHubTotalsStats) string
+}
+
+// statColumns return the headers of the stats section columns
+var statColumns = []func(totals *metrics.GitHubTotalsStats) string{
+	func(_ *metrics.GitHubTotalsStats) string { return "📈 Activity" },
+	func(_ *metrics.GitHubTotalsStats) string { return "👥 Community stats" },
+	func(t *metrics.GitHubTotalsStats) string {
+		return fmt.Sprintf("📚 %d Repositories", t.TotalRepositories)
+	},
+}
+
+// statLines lists every stat line in its default order
+var statLines = []statLine{
+	{
+		Name:   "commits",
+		Column: 0,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("💻 %d Commits", t.TotalCommits)
+		},
+	},
+	{
+		Name:   "pull_request_reviews",
+		Column: 0,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("📋 %d Pull requests reviewed", t.TotalPullRequestReviews)
+		},
+	},
+	{
+		Name:   "pull_requests",
+		Column: 0,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("🔀 %d Pull requests opened", t.TotalPullRequests)
+		},
+	},
+	{
+		Name:   "issues",
+		Column: 0,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("❗ %d Issues opened", t.TotalIssues)
+		},
+	},
+	{
+		Name:   "organizations",
+		Column: 1,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("🏢 Member of %d organizations", t.TotalMemberOfOrganizations)
+		},
+	},
+	{
+		Name:   "following",
+		Column: 1,
+		Text: func(u *metrics.GitHubUserInfo, _ *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("👤 Following %d users", u.Following)
+		},
+	},
+	{
+		Name:   "starred_repos",
+		Column: 1,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("⭐ Starred %d repositories", t.TotalStarredRepos)
+		},
+	},
+	{
+		Name:   "watching",
+		Column: 1,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("👀 Watching %d repositories", t.TotalWatchers)
+		},
+	},
+	{
+		Name:   "sponsors",
+		Column: 2,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("💖 %d Sponsors", t.TotalSponsors)
+		},
+	},
+	{
+		Name:   "stargazers",
+		Column: 2,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("⭐ %d Stargazers", t.TotalStargazers)
+		},
+	},
+	{
+		Name:   "forkers",
+		Column: 2,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("🍴 %d Forkers", t.TotalForks)
+		},
+	},
+	{
+		Name:   "watchers",
+		Column: 2,
+		Text: func(_ *metrics.GitHubUserInfo, t *metrics.GitHubTotalsStats) string {
+			return fmt.Sprintf("👁️ %d Watchers", t.TotalWatchers)
+		},
+	},
+}
+
+// selectedStatLines returns the configured stat lines grouped by column,
+// without the lines the data source of the totals does not provide
+func (r *Renderer) selectedStatLines(totals *metrics.GitHubTotalsStats) [][]statLine {
+	columns := make([][]statLine, len(statColumns))
+	for _, name := range r.Options.statLines() {
+		if slices.Contains(totals.Unavailable, name) {
+			continue
+		}
+		for _, line := range statLines {
+			if line.Name == name {
+				columns[line.Column] = append(columns[line.Column], line)
+			}
+		}
+	}
+	return columns
+}
+
+// statColumnWidths are the widths of the stats section columns
+var statColumnWidths = []float64{230, 230, 150}
+
+// contributionGraphWidth is the width of the month contributions in the stats
+// section
+const contributionGraphWidth = 230.0
+
+// statsLayout returns the stats section: a column per group of stat lines,
+// followed by the contributions of the current month
+func (r *Renderer) statsLayout(
+	userInfo *metrics.GitHubUserInfo,
+	githubTotalsStats *metrics.GitHubTotalsStats,
+	contributionCalendar *metrics.ContributionCalendar,
+	now time.Time,
+) *Node {
+	// The columns wrap onto new lines on narrow cards
+	row := WrapRow(0)
+	for column, lines := range r.selectedStatLines(githubTotalsStats) {
+		if len(lines) == 0 {
+			continue
+		}
+		nodes := []*Node{r.headerLeaf(statColumns[column](githubTotalsStats))}
+		for _, line := range lines {
+			text := line.Text(userInfo, githubTotalsStats)
+			nodes = append(nodes, textLeaf(16, 13, fontText13, r.Profile.TextPrimary, text))
+		}
+		// The right padding keeps truncated lines clear of the next column
+		row.Children = append(
+			row.Children,
+			Column(0, nodes...).WithPadding(Insets{Right: 10}).WithWidth(statColumnWidths[column]),
+		)
+	}
+
+	// Contribution graph
+	row.Children = append(
+		row.Children,
+		r.contributionGraphLayout(contributionCalendar, now).WithWidth(contributionGraphWidth),
+	)
+	for _, column := range row.Children {
+		row.Width += column.Width
+	}
+	return row
+}
+
+// contributionGraphLayout returns the contributions of the current month in
+// rows of 7 days, with the total of the last year below
+func (r *Renderer) contributionGraphLayout(
+	contributionCalendar *metrics.ContributionCalendar,
+	now time.Time,
+) *Node {
+	squares := Leaf(monthSquaresHeight, func(box Box) []svg.Element {
+		return r.generateMonthContributionSquares(contributionCalendar, now, box.X, box.Y)
+	}).WithPadding(Insets{Top: 5, Left: 20})
+	total := textLeaf(
+		25,
+		20,
+		fontText13,
+		r.Profile.TextSecondary,
+		fmt.Sprintf("%d contributions in the last year", contributionCalendar.TotalContributions),
+	)
+
+	return Column(0, r.headerLeaf("📚 Contributions"), squares, total)
+}
+
+// Size of the month contribution squares. The squares take up 5 rows, enough
+// for the longest month.
+const (
+	monthSquareSize    = 11
+	monthSquareGap     = 2
+	monthSquaresHeight = 5 * (monthSquareSize + monthSquareGap)
+)
+
+func (r *Renderer) generateMonthContributionSquares(
+	contributionCalendar *metrics.ContributionCalendar,
+	now time.Time,
+	startX, startY float64,
+) []svg.Element {
+	squares := []svg.Element{}
+
+	// Generate contribution squares pattern
+	squareSize := monthSquareSize
+	squareGap := monthSquareGap
+
+	// Get current month data
+	currentYear, currentMonth := now.Year(), now.Month()
+
+	// Determine days in the current month
+	firstOfMonth := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, time.UTC)
+	firstOfNextMonth := firstOfMonth.AddDate(0, 1, 0)
+	daysInMonth := int(firstOfNextMonth.Sub(firstOfMonth).Hours() / 24)
+
+	// Collect contribution data for the current month
+	monthContributions := getMonthContributions(contributionCalendar, currentYear, currentMonth)
+
+	// Draw contribution squares in rows of 7 days
+	daysPerRow := 7
+	for dayIndex := 0; dayIndex < daysInMonth; dayIndex++ {
+		row := dayIndex / daysPerRow
+		col := dayIndex % daysPerRow
+
+		x := startX + float64(col*(squareSize+squareGap))
+		y := startY + float64(row*(squareSize+squareGap))
+
+		// Get colour for this day if we have data
+		colour := r.Profile.ContributionLevel0 // Default: no contributions
+		if dayIndex < len(monthContributions) && monthContributions[dayIndex].Color != "" {
+			// Map the GitHub API colour to the current colour profile
+			colour = r.Profile.GetContributionColour(monthContributions[dayIndex].Color)
+		}
+
+		squares = append(squares, svg.Rect().
+			Fill(svg.String(colour)).
+			Width(svg.Px(float64(squareSize))).
+			Height(svg.Px(float64(squareSize))).
+			X(svg.Px(x)).
+			Y(svg.Px(y)).
+			RX(svg.Px(2)))
+	}
+
+	return squares
+}
+
+func getMonthContributions(
+	contributionCalendar *metrics.ContributionCalendar,
+	year int,
+	month time.Month,
+) []metrics.ContributionDay {
+	monthContributions := []metrics.ContributionDay{}
+	for _, week := range contributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			dayDate, err := time.Parse("2006-01-02", day.Date)
+			if err != nil {
+				continue
+			}
+			// Check if day is in the specified month
+			if dayDate.Year() == year && dayDate.Month() == month {
+				monthContributions = append(monthContributions, day)
+			}
+		}
+	}
+	return monthContributions
+}
+
+// limitLanguages returns the first limit languages with their percentages
+// renormalized to sum to 100%, or all languages if limit is 0.
+func limitLanguages(languages []metrics.LanguageStat, limit int) []metrics.LanguageStat {
+	if limit <= 0 || len(languages) <= limit {
+		return languages
+	}
+	limited := make([]metrics.LanguageStat, limit)
+	copy(limited, languages[:limit])
+	total := 0.0
+	for _, lang := range limited {
+		total += lang.Percentage
+	}
+	if total > 0 {
+		for i := range limited {
+			limited[i].Percentage = limited[i].Percentage / total * 100.0
+		}
+	}
+	return limited
+}
+
+// otherLanguage is the name of the language grouping the least used languages
+const otherLanguage = "Other"
+
+// groupOtherLanguages returns the first after languages followed by a single
+// Other language with the percentage of the rest, or all languages if there
+// are no more than after
+func groupOtherLanguages(
+	languages []metrics.LanguageStat,
+	after int,
+	colour string,
+) []metrics.LanguageStat {
+	if after <= 0 || len(languages) <= after {
+		return languages
+	}
+	grouped := make([]metrics.LanguageStat, after, after+1)
+	copy(grouped, languages[:after])
+	other := metrics.LanguageStat{Name: otherLanguage, Color: colour}
+	for _, lang := range languages[after:] {
+		other.TotalBytes += lang.TotalBytes
+		other.Percentage += lang.Percentage
+	}
+	return append(grouped, other)
+}
+
+// languagesLayout returns the languages section: the most used languages in
+// the configured chart, by default a bar with a label per language below
+func (r *Renderer) languagesLayout(allLanguages []metrics.LanguageStat) *Node {
+	languages := r.colourLanguages(groupOtherLanguages(
+		limitLanguages(allLanguages, r.Options.languageLimit()),
+		r.Options.otherLanguagesAfter(),
+		r.Profile.TextSecondary,
+	))
+
+	header := Leaf(30, func(box Box) []svg.Element {
+		return []svg.Element{
+			textElement(
+				box.X,
+				box.Y+5,
+				fontHeader15,
+				r.Profile.AccentPrimary,
+				fmt.Sprintf("🗣️ %d Languages", len(allLanguages)),
+				box.Width,
+			),
+			textElement(
+				box.X+box.Width/2,
+				box.Y+25,
+				fontSubtitle12,
+				r.Profile.AccentPrimary,
+				"Most used languages",
+				box.Width,
+			).TextAnchor(svg.String("middle")),
+		}
+	})
+
+	switch r.Options.languageChart() {
+	case LanguageChartDonut:
+		return Column(0, header, r.languagesCircleLayout(languages, languageDonutHole))
+	case LanguageChartPie:
+		return Column(0, header, r.languagesCircleLayout(languages, 0))
+	case LanguageChartBars:
+		return Column(0, header, r.languagesBarsLayout(languages))
+	}
+
+	// Single continuous language bar spanning the full width
+	bar := Leaf(8, func(box Box) []svg.Element {
+		elements := []svg.Element{}
+		currentX := box.X
+		for _, lang := range languages {
+			// Calculate proportional width based on percentage (percentages sum to 100)
+			segmentWidth := (lang.Percentage / 100.0) * box.Width
+			elements = append(elements, svg.Rect().
+				Fill(svg.String(lang.Color)).
+				Width(svg.Px(segmentWidth)).
+				Height(svg.Px(box.Height)).
+				X(svg.Px(currentX)).
+				Y(svg.Px(box.Y)))
+			currentX += segmentWidth
+		}
+		return elements
+	}).WithPadding(Insets{Top: 15})
+
+	// Language labels below the bar, wrapping onto centred lines
+	labels := Centred(0).WithPadding(Insets{Top: 8, Bottom: 4})
+	for _, lang := range languages {
+		labels.Children = append(labels.Children, r.languageLabelLayout(lang))
+	}
+
+	return Column(0, header, bar, labels)
+}
+
+// Spacing of the language labels
+const (
+	languageDotRadius = 4.0
+	// languageDotGap is the gap between the edge of the dot and the name
+	languageDotGap = 6.0
+	// languagePercentageGap is the gap between the name and the percentage
+	languagePercentageGap = 4.0
+	// languageLabelPadding is the space on either side of a label
+	languageLabelPadding = 10.0
+)
+
+// languageLabelLayout returns a leaf with the dot, name and percentage of a
+// language, as wide as its measured text. The name is truncated when the
+// label is wider than the section.
+func (r *Renderer) languageLabelLayout(lang metrics.LanguageStat) *Node {
+	const dotWidth = 2*languageDotRadius + languageDotGap
+	percentage := fmt.Sprintf("%.1f%%", lang.Percentage)
+	percentageWidth := fontText12.Width(percentage)
+	width := dotWidth + fontText12.Width(lang.Name) + languagePercentageGap + percentageWidth
+
+	return Leaf(20, func(box Box) []svg.Element {
+		const baseline = 14.0
+		nameWidth := box.Width - dotWidth - languagePercentageGap - percentageWidth
+		name := fontText12.Truncate(lang.Name, nameWidth)
+		percentageX := box.X + dotWidth + fontText12.Width(name) + languagePercentageGap
+		return []svg.Element{
+			svg.Circle().
+				Fill(svg.String(lang.Color)).
+				CXCYR(box.X+languageDotRadius, box.Y+baseline-4, languageDotRadius, svg.Px),
+			textElement(
+				box.X+dotWidth,
+				box.Y+baseline,
+				fontText12,
+				r.Profile.TextPrimary,
+				name,
+				nameWidth,
+			),
+			textElement(
+				percentageX,
+				box.Y+baseline,
+				fontText12,
+				r.Profile.TextSecondary,
+				percentage,
+				percentageWidth,
+			),
+		}
+	}).WithPadding(Insets{Left: languageLabelPadding, Right: languageLabelPadding}).
+		WithWidth(width + 2*languageLabelPadding)
+}
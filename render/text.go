@@ -0,0 +1,190 @@
+package render
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ellipsis is appended to text truncated to fit its box
+const ellipsis = "…"
+
+// widthTolerance is how far text may overflow the width it is truncated to
+const widthTolerance = 1e-6
+
+// textFont is the default font stack at one of the sizes used on the card,
+// with the advance widths to measure text set in it
+type textFont struct {
+	// Style is the CSS style selecting the font
+	Style string
+	// Size is the font size in px
+	Size float64
+	// advances are the advance widths in px of the printable ASCII
+	// characters, starting at the space
+	advances [asciiCount]float64
+}
+
+// Fonts used on the card
+var (
+	fontText12     = newTextFont(fontStyle12px, 12, regularAdvances)
+	fontSubtitle12 = newTextFont(fontStyleSubtitle12px, 12, boldAdvances)
+	fontText13     = newTextFont(fontStyle13px, 13, regularAdvances)
+	fontHeader15   = newTextFont(fontStyleHeader15px, 15, boldAdvances)
+	fontName18     = newTextFont(fontStyleName18px, 18, boldAdvances)
+)
+
+// Advance widths of the characters outside of the tables, in units per em
+const (
+	// averageAdvance is used for letters of other scripts
+	averageAdvance = 556
+	// wideAdvance is used for East Asian wide characters
+	wideAdvance = 1000
+	// emojiAdvance is used for emoji, which the colour emoji fonts draw
+	// wider than a letter
+	emojiAdvance = 1200
+	// ellipsisAdvance is the advance of the ellipsis truncated text ends in
+	ellipsisAdvance = 1000
+)
+
+const (
+	firstASCII = ' '
+	asciiCount = '~' - firstASCII + 1
+)
+
+// regularAdvances and boldAdvances are the advance widths of the printable
+// ASCII characters in units per em. The system fonts of the stack are not
+// available when rendering, so the tables use the metrics of Helvetica and
+// its metric compatible Arial, the fallbacks of the stack.
+var (
+	regularAdvances = [asciiCount]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+	}
+	boldAdvances = [asciiCount]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611, // 0 to ?
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, // @ to O
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556, // P to _
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, // ` to o
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, // p to ~
+	}
+)
+
+// newTextFont returns the font with the given style, scaling the advance
+// widths in units per em to px at the size
+func newTextFont(style string, size float64, unitsPerEm [asciiCount]int) textFont {
+	font := textFont{Style: style, Size: size}
+	for i, units := range unitsPerEm {
+		font.advances[i] = font.scale(units)
+	}
+	return font
+}
+
+// scale converts units per em to px at the size of the font
+func (f textFont) scale(units int) float64 {
+	return float64(units) * f.Size / 1000
+}
+
+// Width returns the width of the text in px
+func (f textFont) Width(text string) float64 {
+	width := 0.0
+	prev := rune(0)
+	for _, r := range text {
+		width += f.advance(prev, r)
+		prev = r
+	}
+	return width
+}
+
+// Truncate returns the text shortened to fit the width, ending in an ellipsis
+// when shortened. Characters that combine with the one before them, like
+// variation selectors and joined emoji, are kept or dropped together with it.
+func (f textFont) Truncate(text string, width float64) string {
+	// Widths computed from sums of advances are off by rounding errors, so
+	// text measured to exactly fit is not truncated
+	width += widthTolerance
+	if f.Width(text) <= width {
+		return text
+	}
+	available := width - f.Width(ellipsis)
+	if available < 0 {
+		return ""
+	}
+	used := 0.0
+	end := 0
+	prev := rune(0)
+	for i, r := range text {
+		advance := f.advance(prev, r)
+		if advance > 0 && used+advance > available {
+			break
+		}
+		used += advance
+		end = i + utf8.RuneLen(r)
+		prev = r
+	}
+	return strings.TrimRightFunc(text[:end], unicode.IsSpace) + ellipsis
+}
+
+// advance returns the advance width of r in px following prev. Runes that
+// combine with the one before them take no space.
+func (f textFont) advance(prev, r rune) float64 {
+	switch {
+	case r >= firstASCII && r-firstASCII < asciiCount:
+		return f.advances[r-firstASCII]
+	case combines(prev, r):
+		return 0
+	case isEmoji(r):
+		return f.scale(emojiAdvance)
+	case isWide(r):
+		return f.scale(wideAdvance)
+	case r == '…':
+		return f.scale(ellipsisAdvance)
+	default:
+		return f.scale(averageAdvance)
+	}
+}
+
+// combines reports whether r is drawn as part of the character before it
+func combines(prev, r rune) bool {
+	switch {
+	case r == '\u200d', r == '\ufe0e', r == '\ufe0f', r < firstASCII:
+		// Zero width joiner, variation selectors and control characters
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// Skin tone modifiers
+		return true
+	case prev == '\u200d':
+		// The second emoji of a joined sequence
+		return true
+	case isRegionalIndicator(r) && isRegionalIndicator(prev):
+		// The second letter of a flag
+		return true
+	default:
+		return unicode.In(r, unicode.Mn, unicode.Me)
+	}
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isEmoji reports whether r is in one of the blocks of pictographic emoji
+func isEmoji(r rune) bool {
+	return (r >= 0x1f000 && r <= 0x1faff) ||
+		(r >= 0x2300 && r <= 0x23ff) ||
+		(r >= 0x2600 && r <= 0x27bf) ||
+		(r >= 0x2b00 && r <= 0x2bff)
+}
+
+// isWide reports whether r is an East Asian wide character
+func isWide(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115f) ||
+		(r >= 0x2e80 && r <= 0xa4cf) ||
+		(r >= 0xac00 && r <= 0xd7a3) ||
+		(r >= 0xf900 && r <= 0xfaff) ||
+		(r >= 0xff00 && r <= 0xff60)
+}
@@ -0,0 +1,62 @@
+package render
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTextFontWidth(t *testing.T) {
+	for _, tc := range []struct {
+		font textFont
+		text string
+		want float64
+	}{
+		{fontText12, "Go", 12 * (778 + 556) / 1000.0},
+		{fontHeader15, "Go", 15 * (778 + 611) / 1000.0},
+		// Letters outside ASCII count once, not per byte
+		{fontText12, "é", 12 * averageAdvance / 1000.0},
+		{fontText13, "日本", 13 * 2 * wideAdvance / 1000.0},
+		// Variation selectors, skin tones and joined emoji take no space
+		{fontText13, "👁️", 13 * emojiAdvance / 1000.0},
+		{fontText13, "👍🏽", 13 * emojiAdvance / 1000.0},
+		{fontText13, "👩‍💻", 13 * emojiAdvance / 1000.0},
+		{fontText13, "🇬🇧", 13 * emojiAdvance / 1000.0},
+	} {
+		if got := tc.font.Width(tc.text); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf(
+				"expected %q to be %gpx wide at %gpx, got %g",
+				tc.text,
+				tc.want,
+				tc.font.Size,
+				got,
+			)
+		}
+	}
+}
+
+func TestTextFontTruncate(t *testing.T) {
+	if got := fontText12.Truncate("Go", 100); got != "Go" {
+		t.Fatalf("expected text that fits to be kept, got %q", got)
+	}
+	width := 30 - (30 - fontText12.Width("Go"))
+	if got := fontText12.Truncate("Go", width); got != "Go" {
+		t.Fatalf("expected text measured to fit exactly to be kept, got %q", got)
+	}
+
+	got := fontText12.Truncate("Jupyter Notebook", 60)
+	if !strings.HasSuffix(got, ellipsis) || fontText12.Width(got) > 60 {
+		t.Fatalf("expected the text to be truncated to fit, got %q", got)
+	}
+	if got != "Jupyter…" {
+		t.Fatalf("expected trailing spaces to be dropped before the ellipsis, got %q", got)
+	}
+
+	// A joined emoji is dropped as a whole
+	if got := fontText13.Truncate("ab👩‍💻cdef", fontText13.Width("ab👩")); got != "ab…" {
+		t.Fatalf("expected the emoji to be dropped with its joined parts, got %q", got)
+	}
+	if got := fontText13.Truncate("Go", 1); got != "" {
+		t.Fatalf("expected nothing to fit, got %q", got)
+	}
+}
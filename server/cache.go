@@ -0,0 +1,180 @@
+package server
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/metrics"
+)
+
+// Cache stores fetched metrics documents by lower case login. Expiry is
+// decided by the server from the documents' generation time, so a cache only
+// has to store them.
+type Cache interface {
+	Get(login string) (*metrics.Metrics, bool)
+	Put(login string, document *metrics.Metrics) error
+}
+
+// DefaultCacheSize is the number of users whose metrics are kept in memory
+const DefaultCacheSize = 1000
+
+// MemoryCache keeps the metrics documents of the most recently requested
+// users in memory. Documents older than MaxAge are dropped, and once Size
+// documents are cached the least recently used one is evicted for a new one.
+type MemoryCache struct {
+	// Size is the number of documents kept
+	Size int
+	// MaxAge is how long a document is kept from its generation time
+	MaxAge time.Duration
+	// Now returns the current time, used for expiry
+	Now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// recency holds the entries, most recently used first
+	recency *list.List
+}
+
+// memoryEntry is a document cached in a MemoryCache
+type memoryEntry struct {
+	login    string
+	document *metrics.Metrics
+}
+
+// NewMemoryCache creates an empty MemoryCache of size documents, each kept
+// for maxAge
+func NewMemoryCache(size int, maxAge time.Duration) *MemoryCache {
+	return &MemoryCache{
+		Size:    size,
+		MaxAge:  maxAge,
+		Now:     time.Now,
+		entries: map[string]*list.Element{},
+		recency: list.New(),
+	}
+}
+
+// Get returns the cached document of the user, unless it expired
+func (c *MemoryCache) Get(login string) (*metrics.Metrics, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, exists := c.entries[login]
+	if !exists {
+		return nil, false
+	}
+	entry := element.Value.(*memoryEntry)
+	if c.expired(entry, c.Now()) {
+		c.remove(element)
+		return nil, false
+	}
+	c.recency.MoveToFront(element)
+	return entry.document, true
+}
+
+// Put stores the document of the user, dropping expired documents and
+// evicting the least recently used ones beyond the size
+func (c *MemoryCache) Put(login string, document *metrics.Metrics) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.Now()
+	for element := c.recency.Front(); element != nil; {
+		next := element.Next()
+		if c.expired(element.Value.(*memoryEntry), now) {
+			c.remove(element)
+		}
+		element = next
+	}
+
+	if element, exists := c.entries[login]; exists {
+		element.Value.(*memoryEntry).document = document
+		c.recency.MoveToFront(element)
+	} else {
+		c.entries[login] = c.recency.PushFront(&memoryEntry{login: login, document: document})
+	}
+	for c.recency.Len() > max(c.Size, 1) {
+		c.remove(c.recency.Back())
+	}
+	return nil
+}
+
+// expired reports whether the entry is older than MaxAge at now
+func (c *MemoryCache) expired(entry *memoryEntry, now time.Time) bool {
+	return c.MaxAge > 0 && now.Sub(entry.document.GeneratedAt) >= c.MaxAge
+}
+
+// remove removes the element from the cache
+func (c *MemoryCache) remove(element *list.Element) {
+	c.recency.Remove(element)
+	delete(c.entries, element.Value.(*memoryEntry).login)
+}
+
+// DiskCache stores metrics documents as JSON files in a directory, so they
+// survive restarts
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache creates a DiskCache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+// Get reads the cached document of the user. Unreadable files are treated as
+// missing so they are replaced by the next fetch.
+func (c *DiskCache) Get(login string) (*metrics.Metrics, bool) {
+	// #nosec G304 -- The login is validated before it reaches the cache.
+	file, err := os.Open(c.path(login))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			zap.L().Warn("Failed to open cached metrics", zap.Error(err))
+		}
+		return nil, false
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			zap.L().Warn("Failed to close cached metrics", zap.Error(cerr))
+		}
+	}()
+	document, err := metrics.Read(file)
+	if err != nil {
+		zap.L().Warn("Ignoring invalid cached metrics", zap.String("login", login), zap.Error(err))
+		return nil, false
+	}
+	return document, true
+}
+
+// Put writes the document of the user, replacing the cached file atomically
+func (c *DiskCache) Put(login string, document *metrics.Metrics) error {
+	file, err := os.CreateTemp(c.Dir, login+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	if err := metrics.Write(file, document); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(file.Name(), c.path(login)); err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	return nil
+}
+
+func (c *DiskCache) path(login string) string {
+	return filepath.Join(c.Dir, login+".json")
+}
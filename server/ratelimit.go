@@ -0,0 +1,54 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the number of requests per user in fixed windows
+type RateLimiter struct {
+	Limit  int
+	Window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter allows limit requests per user in every window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{Limit: limit, Window: window, windows: map[string]rateWindow{}}
+}
+
+// Allow records a request for the user at now. If the limit is reached it
+// returns false and how long until the next request is allowed.
+func (l *RateLimiter) Allow(login string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window, exists := l.windows[login]
+	if !exists || now.Sub(window.start) >= l.Window {
+		l.removeExpired(now)
+		window = rateWindow{start: now}
+	}
+	if window.count >= l.Limit {
+		return false, window.start.Add(l.Window).Sub(now)
+	}
+	window.count++
+	l.windows[login] = window
+	return true, 0
+}
+
+// removeExpired forgets users whose window has ended, so the map only holds
+// recently requested users
+func (l *RateLimiter) removeExpired(now time.Time) {
+	for login, window := range l.windows {
+		if now.Sub(window.start) >= l.Window {
+			delete(l.windows, login)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Package server serves rendered cards over HTTP, collecting the metrics of
+// any user on demand and caching them.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/render"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+const (
+	// DefaultTTL is how long fetched metrics are served before being fetched again
+	DefaultTTL = time.Hour
+	// DefaultRateLimit is the number of metrics fetches allowed per user per
+	// minute
+	DefaultRateLimit = 60
+)
+
+// Patterns of the logins cards are served for
+var (
+	// GitHubLoginPattern matches valid GitHub logins
+	GitHubLoginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+	// UsernamePattern matches the usernames of GitLab, Gitea and Forgejo,
+	// which may also contain dots and underscores
+	UsernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,254}$`)
+)
+
+// Fetcher collects the metrics of a user. Every metrics.Provider, like
+// *metrics.Collector, is a Fetcher.
+type Fetcher interface {
+	CollectUser(ctx context.Context, login string) (*metrics.Metrics, error)
+}
+
+// Server renders cards for GET /card/{user}.svg. The theme query parameter
+// selects a colour profile, size a card size and sections a comma separated
+// list of sections.
+type Server struct {
+	Fetcher Fetcher
+	Cache   Cache
+	// TTL is how long a fetched document is served, measured from its
+	// generation time
+	TTL time.Duration
+	// Limiter limits the metrics fetches per user, or nil for no limit. Cards
+	// served from the cache are not limited.
+	Limiter *RateLimiter
+	// Profile is the colour profile used when no theme is requested. Theme
+	// overrides are only applied to it.
+	Profile theme.ColourProfile
+	Theme   theme.Overrides
+	// Options are the render options of cards, including the default size
+	Options render.Options
+	// Now returns the current time, used for expiry and rate limiting
+	Now func() time.Time
+	// LoginPattern matches the logins of the data source of the fetcher.
+	// Cards of other logins are not found.
+	LoginPattern *regexp.Regexp
+
+	mu       sync.Mutex
+	inflight map[string]*fetchCall
+}
+
+// fetchCall is a fetch shared by concurrent requests for the same user
+type fetchCall struct {
+	done     chan struct{}
+	document *metrics.Metrics
+	err      error
+}
+
+// rateLimitError is returned when a fetch of the metrics of a user is rate
+// limited
+type rateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("too many fetches, retry after %s", e.retryAfter)
+}
+
+// New creates a Server for GitHub logins with an in-memory cache of
+// DefaultCacheSize users, DefaultTTL and DefaultRateLimit. The cache expires
+// documents by the clock of the server.
+func New(fetcher Fetcher, profile theme.ColourProfile) *Server {
+	s := &Server{
+		Fetcher:      fetcher,
+		TTL:          DefaultTTL,
+		Limiter:      NewRateLimiter(DefaultRateLimit, time.Minute),
+		Profile:      profile,
+		Now:          time.Now,
+		LoginPattern: GitHubLoginPattern,
+	}
+	cache := NewMemoryCache(DefaultCacheSize, DefaultTTL)
+	cache.Now = func() time.Time { return s.Now() }
+	s.Cache = cache
+	return s
+}
+
+// Handler returns the HTTP handler serving cards and the health endpoint
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /card/{file}", s.serveCard)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+func (s *Server) serveCard(w http.ResponseWriter, r *http.Request) {
+	login, isSVG := strings.CutSuffix(r.PathValue("file"), ".svg")
+	if !isSVG || !s.LoginPattern.MatchString(login) {
+		http.NotFound(w, r)
+		return
+	}
+	login = strings.ToLower(login)
+	renderer, err := s.renderer(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := s.Now()
+	document, err := s.metrics(r.Context(), login, now)
+	if err != nil {
+		var limitErr *rateLimitError
+		if errors.As(err, &limitErr) {
+			seconds := int(math.Ceil(limitErr.retryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			http.Error(w, "too many requests for this user", http.StatusTooManyRequests)
+			return
+		}
+		var statusErr *githubapi.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound ||
+			errors.Is(err, metrics.ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		zap.L().Error("Failed to collect metrics", zap.String("login", login), zap.Error(err))
+		http.Error(w, "failed to collect metrics", http.StatusBadGateway)
+		return
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderer.Render(document).WriteTo(&buf); err != nil {
+		zap.L().Error("Failed to render card", zap.String("login", login), zap.Error(err))
+		http.Error(w, "failed to render card", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	maxAge := max(int((s.TTL - now.Sub(document.GeneratedAt)).Seconds()), 0)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		zap.L().Debug("Failed to write card", zap.Error(err))
+	}
+}
+
+// renderer returns a renderer for the theme and sections query parameters
+func (s *Server) renderer(r *http.Request) (*render.Renderer, error) {
+	query := r.URL.Query()
+	profile := s.Profile.WithOverrides(s.Theme)
+	if name := query.Get("theme"); name != "" {
+		requested, exists := theme.LookupColourProfile(name)
+		if !exists {
+			return nil, fmt.Errorf("unknown theme %q", name)
+		}
+		profile = requested
+	}
+
+	renderer := render.New(profile)
+	renderer.Options = s.Options
+	if size := query.Get("size"); size != "" {
+		if !render.IsSize(size) {
+			return nil, fmt.Errorf("unknown size %q", size)
+		}
+		renderer.Options.Size = size
+	}
+	if value := query.Get("sections"); value != "" {
+		sections := []string{}
+		seen := map[string]bool{}
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if !render.IsSection(name) {
+				return nil, fmt.Errorf("unknown section %q", name)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("duplicate section %q", name)
+			}
+			seen[name] = true
+			sections = append(sections, name)
+		}
+		renderer.Options.Sections = sections
+	}
+	return renderer, nil
+}
+
+// metrics returns the cached document of the user, fetching it if it is
+// missing or older than the TTL. Concurrent fetches for a user are shared, and
+// only new fetches count towards the rate limit.
+func (s *Server) metrics(
+	ctx context.Context,
+	login string,
+	now time.Time,
+) (*metrics.Metrics, error) {
+	if document, exists := s.Cache.Get(login); exists && now.Sub(document.GeneratedAt) < s.TTL {
+		return document, nil
+	}
+
+	s.mu.Lock()
+	if s.inflight == nil {
+		s.inflight = map[string]*fetchCall{}
+	}
+	call, exists := s.inflight[login]
+	if !exists {
+		if s.Limiter != nil {
+			if allowed, retryAfter := s.Limiter.Allow(login, now); !allowed {
+				s.mu.Unlock()
+				return nil, &rateLimitError{retryAfter: retryAfter}
+			}
+		}
+		call = &fetchCall{done: make(chan struct{})}
+		s.inflight[login] = call
+		go s.fetch(login, call)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.document, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch collects the metrics of the user and caches them. It is not bound to
+// a request, so a fetch shared by several requests completes even if the
+// first request is cancelled.
+func (s *Server) fetch(login string, call *fetchCall) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.inflight, login)
+		s.mu.Unlock()
+		close(call.done)
+	}()
+
+	zap.L().Info("Fetching metrics", zap.String("login", login))
+	call.document, call.err = s.Fetcher.CollectUser(context.Background(), login)
+	if call.err != nil {
+		return
+	}
+	if err := s.Cache.Put(login, call.document); err != nil {
+		zap.L().Warn("Failed to cache metrics", zap.String("login", login), zap.Error(err))
+	}
+}
+
+// matchesETag reports whether the If-None-Match header matches the ETag
+func matchesETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
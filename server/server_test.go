@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JackPlowman/coding-metrics/githubapi"
+	"github.com/JackPlowman/coding-metrics/metrics"
+	"github.com/JackPlowman/coding-metrics/theme"
+)
+
+var testNow = time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+// fakeFetcher returns a document generated at testNow for every user except
+// "ghost"
+type fakeFetcher struct {
+	mu      sync.Mutex
+	fetches int
+}
+
+func (f *fakeFetcher) CollectUser(ctx context.Context, login string) (*metrics.Metrics, error) {
+	f.mu.Lock()
+	f.fetches++
+	f.mu.Unlock()
+	if login == "ghost" {
+		return nil, &githubapi.StatusError{StatusCode: http.StatusNotFound}
+	}
+	return &metrics.Metrics{
+		SchemaVersion: metrics.SchemaVersion,
+		GeneratedAt:   testNow,
+		User:          &metrics.GitHubUserInfo{Login: login, Name: "Octo Cat"},
+		Totals:        &metrics.GitHubTotalsStats{TotalCommits: 1200},
+		Languages:     []metrics.LanguageStat{{Name: "Go", Color: "#00ADD8", Percentage: 100}},
+		Calendar:      &metrics.ContributionCalendar{},
+	}, nil
+}
+
+func newTestServer(fetcher Fetcher) (*Server, *time.Time) {
+	profile, _ := theme.LookupColourProfile("default")
+	s := New(fetcher, profile)
+	now := testNow
+	s.Now = func() time.Time { return now }
+	return s, &now
+}
+
+func get(s *Server, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeCardCachesMetricsUntilTTL(t *testing.T) {
+	fetcher := &fakeFetcher{}
+	s, now := newTestServer(fetcher)
+
+	rec := get(s, "/card/Octocat.svg", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "image/svg+xml") {
+		t.Fatalf("expected SVG content type, got %q", got)
+	}
+	if !strings.Contains(rec.Body.String(), "Octo Cat") {
+		t.Fatalf("expected the card to be rendered from the fetched metrics")
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+		t.Fatalf("expected max-age of the full TTL, got %q", got)
+	}
+
+	*now = testNow.Add(30 * time.Minute)
+	rec = get(s, "/card/octocat.svg", nil)
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=1800" {
+		t.Fatalf("expected max-age of the remaining TTL, got %q", got)
+	}
+	if fetcher.fetches != 1 {
+		t.Fatalf("expected cached metrics to be reused, got %d fetches", fetcher.fetches)
+	}
+
+	*now = testNow.Add(2 * time.Hour)
+	get(s, "/card/octocat.svg", nil)
+	if fetcher.fetches != 2 {
+		t.Fatalf("expected expired metrics to be fetched again, got %d fetches", fetcher.fetches)
+	}
+}
+
+func TestServeCardHonoursETag(t *testing.T) {
+	s, _ := newTestServer(&fakeFetcher{})
+
+	etag := get(s, "/card/octocat.svg", nil).Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected an ETag")
+	}
+	rec := get(s, "/card/octocat.svg", http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("expected 304 without body, got %d", rec.Code)
+	}
+	dark := get(s, "/card/octocat.svg?theme=dark", http.Header{"If-None-Match": {etag}})
+	if dark.Code != http.StatusOK || dark.Header().Get("ETag") == etag {
+		t.Fatalf("expected another theme to have another ETag")
+	}
+}
+
+func TestServeCardAppliesQueryParameters(t *testing.T) {
+	s, _ := newTestServer(&fakeFetcher{})
+
+	body := get(s, "/card/octocat.svg?sections=languages", nil).Body.String()
+	if !strings.Contains(body, "Go") || strings.Contains(body, "1200 Commits") {
+		t.Fatalf("expected only the languages section to be rendered")
+	}
+
+	tests := map[string]int{
+		"/card/octocat.svg?theme=neon":      http.StatusBadRequest,
+		"/card/octocat.svg?sections=langs":  http.StatusBadRequest,
+		"/card/octocat.svg?size=huge":       http.StatusBadRequest,
+		"/card/octocat.png":                 http.StatusNotFound,
+		"/card/not_a_login.svg":             http.StatusNotFound,
+		"/card/ghost.svg":                   http.StatusNotFound,
+		"/card/octocat.svg?sections=stats,": http.StatusBadRequest,
+	}
+	for target, want := range tests {
+		if got := get(s, target, nil).Code; got != want {
+			t.Errorf("%s: expected %d, got %d", target, want, got)
+		}
+	}
+}
+
+func TestServeCardMatchesLoginsOfTheDataSource(t *testing.T) {
+	s, _ := newTestServer(&fakeFetcher{})
+	if rec := get(s, "/card/octo.cat_dev.svg", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected GitHub logins to be matched by default, got %d", rec.Code)
+	}
+
+	s.LoginPattern = UsernamePattern
+	if rec := get(s, "/card/octo.cat_dev.svg", nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected usernames with dots and underscores, got %d", rec.Code)
+	}
+	if rec := get(s, "/card/.hidden.svg", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected usernames to start with a letter or digit, got %d", rec.Code)
+	}
+}
+
+func TestServeCardRateLimitsFetchesPerUser(t *testing.T) {
+	s, now := newTestServer(&fakeFetcher{})
+	s.Limiter = NewRateLimiter(2, time.Minute)
+
+	for range 5 {
+		if rec := get(s, "/card/octocat.svg", nil); rec.Code != http.StatusOK {
+			t.Fatalf("expected cached cards not to be limited, got %d", rec.Code)
+		}
+	}
+	// Unknown users are not cached, so every request fetches
+	for range 2 {
+		if rec := get(s, "/card/ghost.svg", nil); rec.Code != http.StatusNotFound {
+			t.Fatalf("expected fetches within the limit to be made, got %d", rec.Code)
+		}
+	}
+	*now = testNow.Add(15 * time.Second)
+	rec := get(s, "/card/ghost.svg", nil)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "45" {
+		t.Fatalf(
+			"expected 429 with Retry-After 45, got %d %q",
+			rec.Code,
+			rec.Header().Get("Retry-After"),
+		)
+	}
+	if rec := get(s, "/card/hubot.svg", nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected other users not to be limited, got %d", rec.Code)
+	}
+
+	*now = testNow.Add(time.Minute)
+	if rec := get(s, "/card/ghost.svg", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the limit to reset after the window, got %d", rec.Code)
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsedAndExpired(t *testing.T) {
+	cache := NewMemoryCache(2, time.Hour)
+	now := testNow
+	cache.Now = func() time.Time { return now }
+	fetcher := &fakeFetcher{}
+	for _, login := range []string{"octocat", "hubot"} {
+		document, _ := fetcher.CollectUser(context.Background(), login)
+		_ = cache.Put(login, document)
+	}
+	cache.Get("octocat")
+	document, _ := fetcher.CollectUser(context.Background(), "monalisa")
+	_ = cache.Put("monalisa", document)
+
+	if _, exists := cache.Get("hubot"); exists {
+		t.Fatalf("expected the least recently used document to be evicted")
+	}
+	if _, exists := cache.Get("octocat"); !exists {
+		t.Fatalf("expected the recently used document to be kept")
+	}
+
+	now = testNow.Add(time.Hour)
+	if _, exists := cache.Get("octocat"); exists {
+		t.Fatalf("expected the expired document to be dropped")
+	}
+	_ = cache.Put("octocat", &metrics.Metrics{GeneratedAt: now})
+	if len(cache.entries) != 1 {
+		t.Fatalf("expected expired documents to be dropped on put, got %d", len(cache.entries))
+	}
+}
+
+func TestHealthEndpoint(t *testing.T) {
+	s, _ := newTestServer(&fakeFetcher{})
+	if rec := get(s, "/healthz", nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestDiskCacheRoundTrip(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	if _, exists := cache.Get("octocat"); exists {
+		t.Fatalf("expected an empty cache")
+	}
+	document, _ := (&fakeFetcher{}).CollectUser(context.Background(), "octocat")
+	if err := cache.Put("octocat", document); err != nil {
+		t.Fatalf("failed to cache metrics: %v", err)
+	}
+	cached, exists := cache.Get("octocat")
+	if !exists || cached.User.Login != "octocat" || !cached.GeneratedAt.Equal(testNow) {
+		t.Fatalf("expected the cached document, got %+v", cached)
+	}
+}
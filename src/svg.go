@@ -1,59 +0,0 @@
-package main
-
-import (
-	"os"
-	"path/filepath"
-
-	svg "github.com/twpayne/go-svg"
-
-	"go.uber.org/zap"
-)
-
-func createSVG(svgChildren []svg.Element) *svg.SVGElement {
-	const svgWidth = 1000
-	const svgHeight = 520
-
-	// Add a background rectangle with the profile's background color as the first element
-	bgRect := svg.Rect().
-		Fill(svg.String(currentColourProfile.Background)).
-		Width(svg.Px(svgWidth)).
-		Height(svg.Px(svgHeight)).
-		X(svg.Px(0)).
-		Y(svg.Px(0))
-
-	// Prepend the background to the children
-	allChildren := append([]svg.Element{bgRect}, svgChildren...)
-
-	root := svg.New().WidthHeight(svgWidth, svgHeight, svg.Px).ViewBox(0, 0, svgWidth, svgHeight)
-	if root.Attrs == nil {
-		root.Attrs = map[string]svg.AttrValue{}
-	}
-	root.Attrs["xmlns:xlink"] = svg.String("http://www.w3.org/1999/xlink")
-
-	return root.AppendChildren(
-		allChildren...,
-	)
-}
-
-// createLocalFile creates a new SVG file in the system temp directory.
-// If the temp directory is not writable, it falls back to stdout so the
-// program doesn't panic in read-only environments (CI containers, Actions).
-func createLocalFile(
-	svgElement *svg.SVGElement,
-) *os.File {
-	path := filepath.Join(os.TempDir(), filepath.Clean("output.svg"))
-	// #nosec G304 -- The file path is controlled and safe in this context.
-	file, err := os.Create(path)
-	if err != nil {
-		zap.L().Fatal("Could not create SVG file", zap.Error(err))
-	}
-	zap.L().Info("Writing SVG to file", zap.String("path", path))
-	if _, writeErr := svgElement.WriteTo(file); writeErr != nil {
-		zap.L().Fatal("Failed to write SVG to file", zap.Error(writeErr))
-	}
-	if closeErr := file.Close(); closeErr != nil {
-		zap.L().Error("Failed to close SVG file", zap.Error(closeErr))
-	}
-
-	return file
-}
@@ -1,4 +1,5 @@
-package main
+// Package theme defines the colour profiles used to render metrics cards.
+package theme
 
 import (
 	"strings"
@@ -6,13 +7,14 @@ import (
 	"go.uber.org/zap"
 )
 
-// GitHub default contribution colours (used as baseline for profiles)
+// GitHub default contribution colours (used as baseline for profiles and as the
+// colours returned by the contribution calendar API)
 const (
-	githubContribNone       = "#ebedf0"
-	githubContribLow        = "#9be9a8"
-	githubContribMediumLow  = "#40c463"
-	githubContribMediumHigh = "#30a14e"
-	githubContribHigh       = "#216e39"
+	GitHubContribNone       = "#ebedf0"
+	GitHubContribLow        = "#9be9a8"
+	GitHubContribMediumLow  = "#40c463"
+	GitHubContribMediumHigh = "#30a14e"
+	GitHubContribHigh       = "#216e39"
 )
 
 // ColourProfile defines the colour scheme for the SVG
@@ -28,6 +30,9 @@ type ColourProfile struct {
 	ContributionLevel2 string // Medium-low contributions
 	ContributionLevel3 string // Medium-high contributions
 	ContributionLevel4 string // High contributions
+	// LanguageColours replaces the colours of languages, keyed by lower case
+	// language name
+	LanguageColours map[string]string
 }
 
 // Available colour profiles
@@ -39,11 +44,11 @@ var colourProfiles = map[string]ColourProfile{
 		TextSecondary:      "#656d76",
 		AccentPrimary:      "#0969da",
 		AccentSecondary:    "#1f883d",
-		ContributionLevel0: githubContribNone,
-		ContributionLevel1: githubContribLow,
-		ContributionLevel2: githubContribMediumLow,
-		ContributionLevel3: githubContribMediumHigh,
-		ContributionLevel4: githubContribHigh,
+		ContributionLevel0: GitHubContribNone,
+		ContributionLevel1: GitHubContribLow,
+		ContributionLevel2: GitHubContribMediumLow,
+		ContributionLevel3: GitHubContribMediumHigh,
+		ContributionLevel4: GitHubContribHigh,
 	},
 	"dark": {
 		Name:               "Dark",
@@ -86,12 +91,16 @@ var colourProfiles = map[string]ColourProfile{
 	},
 }
 
+// LookupColourProfile returns the colour profile for the given name and
+// whether it exists. Names are matched case-insensitively.
+func LookupColourProfile(name string) (ColourProfile, bool) {
+	profile, exists := colourProfiles[strings.ToLower(strings.TrimSpace(name))]
+	return profile, exists
+}
+
 // GetColourProfile returns the colour profile for the given name, or the default profile if not found
 func GetColourProfile(name string) ColourProfile {
-	// Normalize the name to lowercase for case-insensitive matching
-	normalizedName := strings.ToLower(strings.TrimSpace(name))
-
-	if profile, exists := colourProfiles[normalizedName]; exists {
+	if profile, exists := LookupColourProfile(name); exists {
 		zap.L().Info("Using colour profile", zap.String("profile", profile.Name))
 		return profile
 	}
@@ -123,15 +132,15 @@ func (cp ColourProfile) GetContributionColour(apiColour string) string {
 	// #216e39 (high)
 
 	switch apiColour {
-	case githubContribNone:
+	case GitHubContribNone:
 		return cp.ContributionLevel0
-	case githubContribLow:
+	case GitHubContribLow:
 		return cp.ContributionLevel1
-	case githubContribMediumLow:
+	case GitHubContribMediumLow:
 		return cp.ContributionLevel2
-	case githubContribMediumHigh:
+	case GitHubContribMediumHigh:
 		return cp.ContributionLevel3
-	case githubContribHigh:
+	case GitHubContribHigh:
 		return cp.ContributionLevel4
 	default:
 		// If we get an unexpected colour, try to map it intelligently
@@ -139,3 +148,79 @@ func (cp ColourProfile) GetContributionColour(apiColour string) string {
 		return cp.ContributionLevel0
 	}
 }
+
+// ContributionLevel returns the contribution level (0-4) for a GitHub API
+// contribution colour
+func ContributionLevel(apiColour string) int {
+	switch apiColour {
+	case GitHubContribNone:
+		return 0
+	case GitHubContribLow:
+		return 1
+	case GitHubContribMediumLow:
+		return 2
+	case GitHubContribMediumHigh:
+		return 3
+	case GitHubContribHigh:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Overrides replaces colours of a colour profile. Empty fields keep the
+// profile's colour.
+type Overrides struct {
+	Background      string
+	TextPrimary     string
+	TextSecondary   string
+	AccentPrimary   string
+	AccentSecondary string
+	// ContributionLevels replaces the colours of contribution levels 0-4 in
+	// order. Empty entries keep the profile's colour.
+	ContributionLevels []string
+	// LanguageColours replaces the colours of languages, keyed by language
+	// name matched case-insensitively. They are added to the language colours
+	// of the profile.
+	LanguageColours map[string]string
+}
+
+// WithOverrides returns a copy of the profile with the overridden colours
+func (cp ColourProfile) WithOverrides(overrides Overrides) ColourProfile {
+	override := func(colour *string, value string) {
+		if value != "" {
+			*colour = value
+		}
+	}
+	override(&cp.Background, overrides.Background)
+	override(&cp.TextPrimary, overrides.TextPrimary)
+	override(&cp.TextSecondary, overrides.TextSecondary)
+	override(&cp.AccentPrimary, overrides.AccentPrimary)
+	override(&cp.AccentSecondary, overrides.AccentSecondary)
+	levels := []*string{
+		&cp.ContributionLevel0,
+		&cp.ContributionLevel1,
+		&cp.ContributionLevel2,
+		&cp.ContributionLevel3,
+		&cp.ContributionLevel4,
+	}
+	for i, value := range overrides.ContributionLevels {
+		if i < len(levels) {
+			override(levels[i], value)
+		}
+	}
+	if len(overrides.LanguageColours) > 0 {
+		languageColours := make(
+			map[string]string,
+			len(cp.LanguageColours)+len(overrides.LanguageColours),
+		)
+		for name, colour := range cp.LanguageColours {
+			languageColours[name] = colour
+		}
+		for name, colour := range overrides.LanguageColours {
+			languageColours[strings.ToLower(name)] = colour
+		}
+		cp.LanguageColours = languageColours
+	}
+	return cp
+}
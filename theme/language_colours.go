@@ -0,0 +1,126 @@
+package theme
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// fallbackPaletteSize is the number of colours in the palette languages
+// without a colour are hashed into
+const fallbackPaletteSize = 12
+
+// LanguageColour returns the colour of a language: the colour set for it in
+// LanguageColours, otherwise the given colour, usually the one GitHub
+// returned, otherwise the fallback colour of its name
+func (cp ColourProfile) LanguageColour(name, colour string) string {
+	if override, exists := cp.LanguageColours[strings.ToLower(name)]; exists {
+		return override
+	}
+	if colour != "" {
+		return colour
+	}
+	return cp.FallbackLanguageColour(name)
+}
+
+// FallbackLanguageColour returns a colour for a language GitHub has no colour
+// for, picked by a hash of its name from a palette harmonised with the
+// profile. The palette has hues evenly spaced around the colour wheel
+// starting at the primary accent, light on dark backgrounds and dark on light
+// ones. A name always gets the same colour in a profile, whatever its case.
+func (cp ColourProfile) FallbackLanguageColour(name string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strings.ToLower(name)))
+	index := hash.Sum32() % fallbackPaletteSize
+
+	hue, saturation := 0.0, 0.65
+	if r, g, b, ok := parseHexColour(cp.AccentPrimary); ok {
+		hue, saturation, _ = rgbToHSL(r, g, b)
+		saturation = math.Min(math.Max(saturation, 0.45), 0.75)
+	}
+	lightness := 0.42
+	if r, g, b, ok := parseHexColour(cp.Background); ok && luminance(r, g, b) < 0.5 {
+		lightness = 0.62
+	}
+	hue = math.Mod(hue+float64(index)*360/fallbackPaletteSize, 360)
+	return hslToHex(hue, saturation, lightness)
+}
+
+// parseHexColour returns the red, green and blue channels, from 0 to 1, of
+// a #rgb, #rrggbb or #rrggbbaa colour
+func parseHexColour(colour string) (r, g, b float64, ok bool) {
+	hex := strings.TrimPrefix(colour, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 && len(hex) != 8 || !strings.HasPrefix(colour, "#") {
+		return 0, 0, 0, false
+	}
+	value, err := strconv.ParseUint(hex[:6], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	r = float64(value>>16&0xff) / 255
+	g = float64(value>>8&0xff) / 255
+	b = float64(value&0xff) / 255
+	return r, g, b, true
+}
+
+// luminance returns the perceived brightness of a colour, from 0 to 1
+func luminance(r, g, b float64) float64 {
+	return 0.299*r + 0.587*g + 0.114*b
+}
+
+// rgbToHSL returns the hue in degrees and the saturation and lightness, from
+// 0 to 1, of a colour
+func rgbToHSL(r, g, b float64) (h, s, l float64) {
+	high, low := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (high + low) / 2
+	if high == low {
+		return 0, 0, l
+	}
+	delta := high - low
+	s = delta / (1 - math.Abs(2*l-1))
+	switch high {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// hslToHex returns the #rrggbb colour of a hue in degrees and a saturation
+// and lightness from 0 to 1
+func hslToHex(h, s, l float64) string {
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = chroma, x
+	case h < 120:
+		r, g = x, chroma
+	case h < 180:
+		g, b = chroma, x
+	case h < 240:
+		g, b = x, chroma
+	case h < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := l - chroma/2
+	channel := func(value float64) int {
+		return int(math.Round((value + m) * 255))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(r), channel(g), channel(b))
+}
@@ -0,0 +1,67 @@
+package theme
+
+import "testing"
+
+func TestLanguageColourPrefersOverridesThenGitHub(t *testing.T) {
+	profile := colourProfiles["default"].WithOverrides(Overrides{
+		LanguageColours: map[string]string{"Go": "#123456"},
+	})
+
+	if got := profile.LanguageColour("go", "#00ADD8"); got != "#123456" {
+		t.Fatalf("expected the override to be matched case-insensitively, got %s", got)
+	}
+	if got := profile.LanguageColour("Rust", "#dea584"); got != "#dea584" {
+		t.Fatalf("expected the GitHub colour, got %s", got)
+	}
+	if got := profile.LanguageColour("Templ", ""); got != profile.FallbackLanguageColour("Templ") {
+		t.Fatalf("expected the fallback colour, got %s", got)
+	}
+	if len(colourProfiles["default"].LanguageColours) != 0 {
+		t.Fatalf("expected the overrides not to change the built-in profile")
+	}
+}
+
+func TestFallbackLanguageColourIsDeterministicPerProfile(t *testing.T) {
+	light, dark := colourProfiles["default"], colourProfiles["dark"]
+
+	if light.FallbackLanguageColour("Templ") != light.FallbackLanguageColour("TEMPL") {
+		t.Fatalf("expected the same colour whatever the case of the name")
+	}
+	colours := map[string]bool{}
+	for _, name := range []string{"Templ", "Gleam", "Odin", "Mojo", "Hare", "Roc"} {
+		colour := light.FallbackLanguageColour(name)
+		if _, _, _, ok := parseHexColour(colour); !ok {
+			t.Fatalf("expected a hex colour for %s, got %q", name, colour)
+		}
+		colours[colour] = true
+	}
+	if len(colours) < 2 {
+		t.Fatalf("expected names to be spread over the palette, got %v", colours)
+	}
+
+	r, g, b, _ := parseHexColour(light.FallbackLanguageColour("Templ"))
+	lightLuminance := luminance(r, g, b)
+	r, g, b, _ = parseHexColour(dark.FallbackLanguageColour("Templ"))
+	if darkLuminance := luminance(r, g, b); darkLuminance <= lightLuminance {
+		t.Fatalf(
+			"expected a lighter colour on a dark background, got %f and %f",
+			lightLuminance,
+			darkLuminance,
+		)
+	}
+}
+
+func TestHSLRoundTrip(t *testing.T) {
+	for _, colour := range []string{"#ff0000", "#0969da", "#39d353", "#656d76"} {
+		r, g, b, ok := parseHexColour(colour)
+		if !ok {
+			t.Fatalf("expected %s to be parsed", colour)
+		}
+		if got := hslToHex(rgbToHSL(r, g, b)); got != colour {
+			t.Fatalf("expected %s to round trip, got %s", colour, got)
+		}
+	}
+	if _, _, _, ok := parseHexColour("0969da"); ok {
+		t.Fatalf("expected a colour without # to be rejected")
+	}
+}